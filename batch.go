@@ -0,0 +1,27 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package s3
+
+type Batch struct {
+	Goroutines uint8
+	Rate       uint
+}
+
+func (batch Batch) GetBatchInterface() {}
+func (batch Batch) GoroutinesNum() *uint8 {
+	if batch.Goroutines == 0 {
+		return nil
+	}
+	return &batch.Goroutines
+}
+func (batch Batch) RateNum() *uint {
+	if batch.Rate == 0 {
+		return nil
+	}
+	return &batch.Rate
+}
@@ -8,13 +8,18 @@
 package bucket
 
 import (
+	_bufio "bufio"
+	_bytes "bytes"
+	_context "context"
 	_md5 "crypto/md5"
 	_base64 "encoding/base64"
 	_xml "encoding/xml"
 	_err "errors"
 	_fmt "fmt"
 	_io "io"
+	_ioutil "io/ioutil"
 	_http "net/http"
+	_url "net/url"
 	_os "os"
 	_fs "path/filepath"
 	_enum "s3/enum"
@@ -23,7 +28,6 @@ import (
 	_resp "s3/request/response"
 	_strconv "strconv"
 	_str "strings"
-	_sync "sync"
 	_time "time"
 )
 
@@ -35,12 +39,28 @@ type Bucket struct {
 	s3   _S3
 	name string
 	uri  string
+	ctx  _context.Context
 }
 
 type _S3 interface {
 	GetS3Interface()
+	Context() _context.Context
 	Bucket(name string) *Bucket
 	Signature(str string) string
+	SignatureV2(str string) string
+	SignatureV4(date string, region string, service string, str string) string
+	AccessKey() string
+	IsV4() bool
+	RegionStr() string
+	BucketRegionStr(bucket string) string
+	HasBucketRegion(bucket string) bool
+	SetBucketRegion(bucket string, region string)
+	HttpClient() *_http.Client
+	RetryAttempts() uint
+	RetryDelay(attempt uint) _time.Duration
+	SchemeStr() string
+	HostStr() string
+	IsPathStyle() bool
 }
 type _Where interface {
 	GetWhereInterface()
@@ -48,6 +68,54 @@ type _Where interface {
 	NextKeyStr() *string
 	ExcludeStr() *string
 	LimitNum() *uint64
+	TokenStr() *string
+	StartAfterStr() *string
+	IsFetchOwner() bool
+}
+type _Multipart interface {
+	GetMultipartInterface()
+	PartSizeNum() *uint64
+	GoroutinesNum() *uint8
+	RetryNum() *uint8
+	CheckpointStr() *string
+	RateNum() *uint
+}
+type _Batch interface {
+	GetBatchInterface()
+	GoroutinesNum() *uint8
+	RateNum() *uint
+}
+type _Save interface {
+	GetSaveInterface()
+	ModeVal() *_os.FileMode
+	IsKeepTime() bool
+	IsResume() bool
+}
+type _Cache string
+type _Meta interface {
+	GetMetaInterface()
+	MetaMap() map[string]string
+}
+type _Tags interface {
+	GetTagsInterface()
+	TagsMap() map[string]string
+}
+type _Prune interface {
+	GetPruneInterface()
+	PrefixStr() *string
+	OlderThanNum() *uint
+	MinSizeNum() *uint64
+	MaxSizeNum() *uint64
+	PatternStr() *string
+	IsDryRun() bool
+	ProgressFunc() func(done uint64, total uint64)
+}
+type _Policy interface {
+	GetPolicyInterface()
+	AclVal() _enum.Acl
+	ContentTypeStr() *string
+	MinSizeNum() *uint64
+	MaxSizeNum() *uint64
 }
 
 func mapTrim(strs []string) []string {
@@ -92,6 +160,106 @@ func getFileContentType(file string) (string, error) {
 
 	return _http.DetectContentType(buf), nil
 }
+func getReaderMD5(reader _io.ReadSeeker, size uint64) (string, error) {
+	offset, err := reader.Seek(0, _io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+
+	h := _md5.New()
+	if _, err := _io.CopyN(h, reader, int64(size)); err != nil {
+		return "", err
+	}
+
+	if _, err := reader.Seek(offset, _io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return _base64.StdEncoding.EncodeToString(h.Sum(nil)), nil
+}
+func getReaderContentType(reader _io.ReadSeeker) (string, error) {
+	offset, err := reader.Seek(0, _io.SeekCurrent)
+	if err != nil {
+		return "", err
+	}
+
+	buf := make([]byte, 512)
+	n, err := _io.ReadFull(reader, buf)
+	if err != nil && err != _io.EOF && err != _io.ErrUnexpectedEOF {
+		return "", err
+	}
+
+	if _, err := reader.Seek(offset, _io.SeekStart); err != nil {
+		return "", err
+	}
+
+	return _http.DetectContentType(buf[:n]), nil
+}
+func parseArgs(args []interface{}) (_enum.Acl, string, string) {
+	acl := _enum.ACL_PRIVATE
+	cache := ""
+	cType := ""
+
+	for _, arg := range args {
+		if val, ok := arg.(_enum.Acl); ok {
+			acl = val
+		}
+
+		if val, ok := arg.(int); ok && val > 0 {
+			cache = _fmt.Sprintf("max-age=%d", val)
+		}
+
+		if val, ok := arg.(_Cache); ok && val != "" {
+			cache = string(val)
+		}
+
+		if val, ok := arg.(string); ok && val != "" {
+			cType = val
+		}
+	}
+
+	return acl, cache, cType
+}
+func parseObjectArgs(args []interface{}) (*_enum.Directive, _enum.Storage, map[string]string, string) {
+	var directive *_enum.Directive = nil
+	storage := _enum.STORAGE_STANDARD
+	metas := map[string]string{}
+	tags := _url.Values{}
+
+	for _, arg := range args {
+		switch val := arg.(type) {
+		case _enum.Directive:
+			directive = &val
+		case _enum.Storage:
+			storage = val
+		case _Meta:
+			if val == nil {
+				continue
+			}
+			for k, v := range val.MetaMap() {
+				metas[_str.ToLower(k)] = v
+			}
+		case _Tags:
+			if val == nil {
+				continue
+			}
+			for k, v := range val.TagsMap() {
+				tags.Set(k, v)
+			}
+		}
+	}
+
+	return directive, storage, metas, tags.Encode()
+}
+func copyDirective(args []interface{}) _enum.Directive {
+	if directive, _, _, _ := parseObjectArgs(args); directive != nil {
+		return *directive
+	}
+	return _enum.DIRECTIVE_COPY
+}
+func copySource(src *Bucket) string {
+	return _fmt.Sprintf("/%s/%s", src.name, _str.Replace(_str.Replace(_url.QueryEscape(src.uri), "+", "%20", -1), "%2F", "/", -1))
+}
 func copy(s3 _S3, src *Bucket, dest *Bucket, args ...interface{}) error {
 	if dest == nil {
 		return _err.New("目的地的 Bucket 錯誤")
@@ -107,20 +275,118 @@ func copy(s3 _S3, src *Bucket, dest *Bucket, args ...interface{}) error {
 		return _err.New(_fmt.Sprintf("被複製的沒有指定 S3 路徑"))
 	}
 
-	acl := _enum.ACL_PRIVATE
-	cache := ""
+	if copyDirective(args) != _enum.DIRECTIVE_REPLACE {
+		_, cache, cType := parseArgs(args)
+		_, _, metas, _ := parseObjectArgs(args)
+		if cache != "" || cType != "" || len(metas) > 0 {
+			return _err.New("修改快取時間、Content-Type 或 Meta 時需要指定 DIRECTIVE_REPLACE")
+		}
+	}
 
-	for _, arg := range args {
-		if val, ok := arg.(_enum.Acl); ok {
-			acl = val
+	meta, err := src.Meta()
+	if err != nil {
+		return err
+	}
+
+	if meta.ContentLength > PART_MAX_SIZE {
+		return dest.multipartCopy(src, meta, args...)
+	}
+
+	acl, cache, cType := parseArgs(args)
+	_, storage, metas, tagging := parseObjectArgs(args)
+	directive := copyDirective(args)
+
+	request := _req.New(s3).Context(dest.ctx).Bucket(dest.name).Uri(dest.uri).Method(_enum.METHOD_PUT).SetAmzHeader("x-amz-acl", acl.Str()).SetAmzHeader("x-amz-copy-source", copySource(src)).SetAmzHeader("x-amz-metadata-directive", directive.Str())
+
+	if directive == _enum.DIRECTIVE_REPLACE {
+		if cType == "" {
+			cType = meta.ContentType
 		}
+		request = request.SetHeader("Content-Type", cType).SetHeader("Cache-Control", cache)
+		for key, val := range metas {
+			request = request.SetAmzHeader(key, val)
+		}
+	}
 
-		if val, ok := arg.(int); ok && val > 0 {
-			cache = _fmt.Sprintf("%d", val)
+	if storage != _enum.STORAGE_STANDARD {
+		request = request.SetAmzHeader("x-amz-storage-class", storage.Str())
+	}
+
+	if tagging != "" {
+		request = request.SetAmzHeader("x-amz-tagging", tagging).SetAmzHeader("x-amz-tagging-directive", "REPLACE")
+	}
+
+	response := request.Response()
+	if err := response.IsSuccess(); err != nil {
+		return err
+	}
+
+	return errorOfBody(response.StatusCode, response.BodyBytes)
+}
+func parseMeta(headers map[string]string) (*_model.FileMeta, error) {
+	tmp1 := uint64(0)
+	if val, ok := headers["Content-Length"]; ok {
+		switch num, err := _strconv.ParseInt(val, 10, 64); true {
+		case err != nil:
+			return nil, _err.New(_fmt.Sprintf("資訊錯誤，Content-Length 格式有誤，Message：%s", err))
+		case num < 0:
+			return nil, _err.New(_fmt.Sprintf("資訊錯誤，Content-Length 格式有誤，其值 %d < 0：", num))
+		default:
+			tmp1 = uint64(num)
 		}
+	} else {
+		return nil, _err.New("資訊有缺，缺少 Content-Length")
+	}
+
+	tmp2 := uint64(0)
+	if val, ok := headers["Last-Modified"]; ok {
+		switch time, err := _time.Parse("Mon, 02 Jan 2006 15:04:05 GMT", val); true {
+		case err != nil:
+			return nil, _err.New(_fmt.Sprintf("Last-Modified 格式有誤，Message：%s", err))
+		default:
+			tmp2 = uint64(time.Unix())
+		}
+	} else {
+		return nil, _err.New("資訊有缺，缺少 Last-Modified")
+	}
+
+	tmp3 := ""
+	if val, ok := headers["Etag"]; ok {
+		tmp3 = _str.Trim(val, "\"")
+	} else {
+		return nil, _err.New("資訊有缺，缺少 Etag")
 	}
 
-	return _req.New(s3).Bucket(dest.name).Uri(dest.uri).Method(_enum.METHOD_PUT).SetAmzHeader("x-amz-acl", acl.Str()).SetAmzHeader("x-amz-copy-source", _fmt.Sprintf("/%s/%s", src.name, src.uri)).SetAmzHeader("x-amz-metadata-directive", "COPY").SetHeader("Cache-Control", cache).Response().IsSuccess()
+	tmp4 := ""
+	if val, ok := headers["Content-Type"]; ok {
+		tmp4 = val
+	} else {
+		return nil, _err.New("資訊有缺，缺少 Etag")
+	}
+
+	tmp5 := map[string]string{}
+	for key, val := range headers {
+		if _str.HasPrefix(_str.ToLower(key), "x-amz-meta-") {
+			tmp5[_str.ToLower(key[len("x-amz-meta-"):])] = val
+		}
+	}
+
+	sse, _ := headers["X-Amz-Server-Side-Encryption"]
+	_, ssec := headers["X-Amz-Server-Side-Encryption-Customer-Algorithm"]
+
+	return &_model.FileMeta{
+		ContentLength:      tmp1,
+		Time:               tmp2,
+		Md5:                tmp3,
+		ContentType:        tmp4,
+		CacheControl:       headers["Cache-Control"],
+		ContentDisposition: headers["Content-Disposition"],
+		ContentEncoding:    headers["Content-Encoding"],
+		ContentLanguage:    headers["Content-Language"],
+		Expires:            headers["Expires"],
+		Meta:               tmp5,
+		IsMd5:              isMD5ETag(tmp3) && (sse == "" || sse == "AES256") && !ssec,
+	}, nil
 }
 
 func New(name string, s3 _S3) (*Bucket, error) {
@@ -128,9 +394,50 @@ func New(name string, s3 _S3) (*Bucket, error) {
 	if len(dirs) <= 0 {
 		return nil, _err.New("Bucket 名稱或路徑格式錯誤")
 	}
-	return &Bucket{s3: s3, name: dirs[0], uri: _str.Join(dirs[1:], "/")}, nil
+	ctx := _context.Background()
+	if s3 != nil {
+		ctx = s3.Context()
+	}
+	return &Bucket{s3: s3, name: dirs[0], uri: _str.Join(dirs[1:], "/"), ctx: ctx}, nil
+}
+
+func (bucket *Bucket) resolveRegion() error {
+	if bucket.s3.HostStr() != "" || bucket.s3.HasBucketRegion(bucket.name) {
+		return nil
+	}
+
+	if _, err := bucket.Region(); err != nil {
+		return _fmt.Errorf("無法取得 Bucket %s 的區域，Message：%w", bucket.name, err)
+	}
+	return nil
 }
+func (bucket *Bucket) presign(method _enum.Method, expire _time.Duration, contentType string) (string, error) {
+	if bucket == nil {
+		return "", _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return "", _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
 
+	if err := bucket.resolveRegion(); err != nil {
+		return "", err
+	}
+
+	return _req.New(bucket.s3).Bucket(bucket.name).Uri(bucket.uri).Method(method).UseSSL(true).SetHeader("Content-Type", contentType).Presign(expire)
+}
+func (bucket *Bucket) WithContext(ctx _context.Context) *Bucket {
+	if bucket == nil || ctx == nil {
+		return bucket
+	}
+	return &Bucket{s3: bucket.s3, name: bucket.name, uri: bucket.uri, ctx: ctx}
+}
+func (bucket *Bucket) Context() _context.Context {
+	if bucket == nil || bucket.ctx == nil {
+		return _context.Background()
+	}
+	return bucket.ctx
+}
 func (bucket *Bucket) String() string {
 	if bucket == nil {
 		return ""
@@ -155,7 +462,11 @@ func (bucket *Bucket) Create(args ...interface{}) error {
 		}
 	}
 
-	req := _req.New(bucket.s3).Bucket(bucket.name).Method(_enum.METHOD_PUT).SetAmzHeader("x-amz-acl", acl.Str())
+	if loc != _enum.LOC_NANO {
+		bucket.s3.SetBucketRegion(bucket.name, loc.Region())
+	}
+
+	req := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Method(_enum.METHOD_PUT).SetAmzHeader("x-amz-acl", acl.Str())
 
 	if loc != _enum.LOC_NANO {
 		encoder, err := _xml.MarshalIndent(struct {
@@ -177,12 +488,54 @@ func (bucket *Bucket) Create(args ...interface{}) error {
 
 	return nil
 }
+func (bucket *Bucket) Region() (string, error) {
+	if bucket == nil {
+		return "", _err.New("錯誤的 Bucket")
+	}
+
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Method(_enum.METHOD_HEAD).Response()
+	if response.Error != nil {
+		return "", response.Error
+	}
+
+	if region, ok := response.Headers["X-Amz-Bucket-Region"]; ok && region != "" {
+		bucket.s3.SetBucketRegion(bucket.name, region)
+		return region, nil
+	}
+
+	response = _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Method(_enum.METHOD_GET).Parameter("location", "").Response()
+	if err := response.IsSuccess(); err != nil {
+		return "", err
+	}
+
+	var result *struct {
+		Location string `xml:",chardata"`
+	} = nil
+
+	if err := _xml.Unmarshal(response.BodyBytes, &result); err != nil {
+		return "", _err.New(_fmt.Sprintf("編譯 XML 失敗，Message：%s", err))
+	}
+
+	region := _str.Trim(result.Location, " ")
+	switch region {
+	case "":
+		region = _enum.LOC_NANO.Region()
+	case _enum.LOC_EU.Str():
+		region = _enum.LOC_EU.Region()
+	}
+
+	bucket.s3.SetBucketRegion(bucket.name, region)
+	return region, nil
+}
 func (bucket *Bucket) Delete() error {
 	if bucket == nil {
 		return _err.New("錯誤的 Bucket")
 	}
 
-	err := _req.New(bucket.s3).Bucket(bucket.name).Method(_enum.METHOD_DELETE).Response().IsSuccess([]uint16{200, 204})
+	err := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Method(_enum.METHOD_DELETE).Response().IsSuccess([]uint16{200, 204})
+	if err == nil {
+		bucket.s3.SetBucketRegion(bucket.name, "")
+	}
 	return err
 }
 func (bucket *Bucket) Files(wheres ..._Where) ([]*_model.File, error) {
@@ -196,6 +549,10 @@ func (bucket *Bucket) Files(wheres ..._Where) ([]*_model.File, error) {
 	var limit *uint64
 
 	if len(wheres) > 0 && wheres[0] != nil {
+		if wheres[0].TokenStr() != nil || wheres[0].StartAfterStr() != nil || wheres[0].IsFetchOwner() {
+			return files, _err.New("Token、StartAfter 與 FetchOwner 僅適用於 List")
+		}
+
 		prefix = wheres[0].PrefixStr()
 		nextKey = wheres[0].NextKeyStr()
 		exclude = wheres[0].ExcludeStr()
@@ -203,7 +560,7 @@ func (bucket *Bucket) Files(wheres ..._Where) ([]*_model.File, error) {
 	}
 
 	for {
-		req := _req.New(bucket.s3).Bucket(bucket.name).Method(_enum.METHOD_GET)
+		req := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Method(_enum.METHOD_GET)
 
 		if prefix != nil {
 			req.Parameter("prefix", *prefix)
@@ -261,10 +618,12 @@ func (bucket *Bucket) Files(wheres ..._Where) ([]*_model.File, error) {
 			}
 
 			file := &_model.File{
-				Key:  content.Key,
-				Time: uint64(time.Unix()),
-				Md5:  _str.Trim(content.ETag, "\""),
-				Size: content.Size,
+				Key:       content.Key,
+				Time:      uint64(time.Unix()),
+				Md5:       _str.Trim(content.ETag, "\""),
+				Size:      content.Size,
+				OwnerId:   content.Owner.Id,
+				OwnerName: content.Owner.Name,
 			}
 			files = append(files, file)
 			nextKey = &file.Key
@@ -314,20 +673,79 @@ func (bucket *Bucket) Put(path string, args ...interface{}) error {
 		return _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
 	}
 
-	acl := _enum.ACL_PRIVATE
-	cache := ""
+	acl, cache, contentType := parseArgs(args)
+	if contentType != "" {
+		cType = contentType
+	}
 
-	for _, arg := range args {
-		if val, ok := arg.(_enum.Acl); ok {
-			acl = val
+	_, storage, metas, tagging := parseObjectArgs(args)
+
+	request := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_PUT).SetHeader("Content-Type", cType).SetHeader("Content-MD5", cMd5).SetAmzHeader("x-amz-acl", acl.Str()).SetFile(path, uint64(stat.Size())).SetHeader("Cache-Control", cache).SetAmzHeader("x-amz-tagging", tagging)
+	for key, val := range metas {
+		request = request.SetAmzHeader(key, val)
+	}
+	if storage != _enum.STORAGE_STANDARD {
+		request = request.SetAmzHeader("x-amz-storage-class", storage.Str())
+	}
+
+	return request.Response().IsSuccess()
+}
+func (bucket *Bucket) PutReader(reader _io.Reader, size int64, args ...interface{}) error {
+	if bucket == nil {
+		return _err.New("錯誤的 Bucket")
+	}
+
+	if reader == nil {
+		return _err.New("錯誤的 Reader")
+	}
+
+	if bucket.uri == "" {
+		return _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	if size < 0 {
+		return bucket.UploadReader(reader, size, args...)
+	}
+
+	acl, cache, cType := parseArgs(args)
+	cMd5 := ""
+
+	if seeker, ok := reader.(_io.ReadSeeker); ok {
+		md5, err := getReaderMD5(seeker, uint64(size))
+		if err != nil {
+			return _err.New(_fmt.Sprintf("無法取得資料的 MD5 結果，Message：%s", err))
 		}
+		cMd5 = md5
 
-		if val, ok := arg.(int); ok && val > 0 {
-			cache = _fmt.Sprintf("%d", val)
+		if cType == "" {
+			if cType, err = getReaderContentType(seeker); err != nil {
+				return _err.New(_fmt.Sprintf("無法取得資料的 Content Type，Message：%s", err))
+			}
 		}
+	} else if cType == "" {
+		buffer := _bufio.NewReaderSize(reader, 512)
+		head, _ := buffer.Peek(512)
+		cType = _http.DetectContentType(head)
+		reader = buffer
 	}
 
-	return _req.New(bucket.s3).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_PUT).SetHeader("Content-Type", cType).SetHeader("Content-MD5", cMd5).SetAmzHeader("x-amz-acl", acl.Str()).SetFile(path, uint64(stat.Size())).SetHeader("Cache-Control", cache).Response().IsSuccess()
+	_, storage, metas, tagging := parseObjectArgs(args)
+
+	request := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_PUT).SetHeader("Content-Type", cType).SetHeader("Content-MD5", cMd5).SetAmzHeader("x-amz-acl", acl.Str()).SetReader(reader, uint64(size)).SetHeader("Cache-Control", cache).SetAmzHeader("x-amz-tagging", tagging)
+	for key, val := range metas {
+		request = request.SetAmzHeader(key, val)
+	}
+	if storage != _enum.STORAGE_STANDARD {
+		request = request.SetAmzHeader("x-amz-storage-class", storage.Str())
+	}
+
+	return request.Response().IsSuccess()
+}
+func (bucket *Bucket) PutBytes(data []byte, args ...interface{}) error {
+	return bucket.PutReader(_bytes.NewReader(data), int64(len(data)), args...)
+}
+func (bucket *Bucket) PutString(str string, args ...interface{}) error {
+	return bucket.PutReader(_str.NewReader(str), int64(len(str)), args...)
 }
 func (bucket *Bucket) Del() error {
 	if bucket == nil {
@@ -338,7 +756,7 @@ func (bucket *Bucket) Del() error {
 		return _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
 	}
 
-	return _req.New(bucket.s3).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_DELETE).Response().IsSuccess([]uint16{200, 204})
+	return _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_DELETE).Response().IsSuccess([]uint16{200, 204})
 }
 func (bucket *Bucket) Meta() (*_model.FileMeta, error) {
 	if bucket == nil {
@@ -349,57 +767,37 @@ func (bucket *Bucket) Meta() (*_model.FileMeta, error) {
 		return nil, _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
 	}
 
-	response := _req.New(bucket.s3).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_HEAD).Response()
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_HEAD).Response()
 	if err := response.IsSuccess(); err != nil {
 		return nil, err
 	}
 
-	tmp1 := uint64(0)
-	if val, ok := response.Headers["Content-Length"]; ok {
-		switch num, err := _strconv.ParseInt(val, 10, 64); true {
-		case err != nil:
-			return nil, _err.New(_fmt.Sprintf("資訊錯誤，Content-Length 格式有誤，Message：%s", err))
-		case num < 0:
-			return nil, _err.New(_fmt.Sprintf("資訊錯誤，Content-Length 格式有誤，其值 %d < 0：", num))
-		default:
-			tmp1 = uint64(num)
-		}
-	} else {
-		return nil, _err.New("資訊有缺，缺少 Content-Length")
-	}
+	return parseMeta(response.Headers)
+}
+func (bucket *Bucket) tags() (map[string]string, error) {
+	tags := map[string]string{}
 
-	tmp2 := uint64(0)
-	if val, ok := response.Headers["Last-Modified"]; ok {
-		switch time, err := _time.Parse("Mon, 02 Jan 2006 15:04:05 GMT", val); true {
-		case err != nil:
-			return nil, _err.New(_fmt.Sprintf("Last-Modified 格式有誤，Message：%s", err))
-		default:
-			tmp2 = uint64(time.Unix())
-		}
-	} else {
-		return nil, _err.New("資訊有缺，缺少 Last-Modified")
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_GET).Parameter("tagging", "").Response()
+	if err := response.IsSuccess(); err != nil {
+		return tags, _fmt.Errorf("無法取得 %s/%s 的標籤，Message：%w", bucket.name, bucket.uri, err)
 	}
 
-	tmp3 := ""
-	if val, ok := response.Headers["Etag"]; ok {
-		tmp3 = _str.Trim(val, "\"")
-	} else {
-		return nil, _err.New("資訊有缺，缺少 Etag")
+	var result *struct {
+		Tags []struct {
+			Key   string `xml:"Key"`
+			Value string `xml:"Value"`
+		} `xml:"TagSet>Tag"`
+	} = nil
+
+	if err := _xml.Unmarshal(response.BodyBytes, &result); err != nil {
+		return tags, _err.New(_fmt.Sprintf("編譯 XML 失敗，Message：%s", err))
 	}
 
-	tmp4 := ""
-	if val, ok := response.Headers["Content-Type"]; ok {
-		tmp4 = val
-	} else {
-		return nil, _err.New("資訊有缺，缺少 Etag")
+	for _, tag := range result.Tags {
+		tags[tag.Key] = tag.Value
 	}
 
-	return &_model.FileMeta{
-		ContentLength: tmp1,
-		Time:          tmp2,
-		Md5:           tmp3,
-		ContentType:   tmp4,
-	}, nil
+	return tags, nil
 }
 func (bucket *Bucket) File() (*_resp.Response, error) {
 	if bucket == nil {
@@ -410,7 +808,7 @@ func (bucket *Bucket) File() (*_resp.Response, error) {
 		return nil, _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
 	}
 
-	response := _req.New(bucket.s3).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_GET).Response()
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_GET).Response()
 
 	if err := response.IsSuccess(); err != nil {
 		return nil, err
@@ -418,92 +816,187 @@ func (bucket *Bucket) File() (*_resp.Response, error) {
 
 	return response, nil
 }
-func (bucket *Bucket) Save(path string, modes ..._os.FileMode) error {
-	resp, err := bucket.File()
+func (bucket *Bucket) Stream() (_io.ReadCloser, *_model.FileMeta, error) {
+	if bucket == nil {
+		return nil, nil, _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return nil, nil, _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_GET).Stream(true).Response()
+
+	if err := response.IsSuccess(); err != nil {
+		return nil, nil, err
+	}
+
+	meta, err := parseMeta(response.Headers)
 	if err != nil {
-		return err
+		response.Body.Close()
+		return nil, nil, err
 	}
 
-	path, err = _fs.Abs(path)
+	return response.Body, meta, nil
+}
+func (bucket *Bucket) Save(path string, args ...interface{}) error {
+	if bucket == nil {
+		return _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	path, err := _fs.Abs(path)
 	if err != nil {
 		return _err.New(_fmt.Sprintf("無法取得 %s 檔案的絕對位置，Message：%s", path, err))
 	}
 
-	file, err := _os.Create(path)
-	if err != nil {
-		return _err.New(_fmt.Sprintf("無法取得 %s 檔案的資源，Message：%s", path, err))
+	mode, keepTime, resume := parseSave(args)
+	if resume {
+		return bucket.saveResume(path, mode, keepTime)
 	}
 
-	defer file.Close()
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_GET).SetHeader("Accept-Encoding", "identity").SetAmzHeader("x-amz-checksum-mode", "ENABLED").Stream(true).Response()
 
-	_, err = file.Write(resp.BodyBytes)
-	if err != nil {
-		return _err.New(_fmt.Sprintf("%s 檔案寫入失敗，Message：%s", path, err))
+	if err := response.IsSuccess(); err != nil {
+		return err
 	}
-	file.Sync()
+	defer response.Body.Close()
 
-	var mode _os.FileMode = 0644
-	if len(modes) > 0 {
-		mode = modes[0]
+	meta, err := parseMeta(response.Headers)
+	if err != nil {
+		return err
 	}
-	err = _os.Chmod(path, mode)
+
+	file, err := _ioutil.TempFile(_fs.Dir(path), _fmt.Sprintf(".%s.*%s", _fs.Base(path), TEMP_EXT))
 	if err != nil {
-		return _err.New(_fmt.Sprintf("%s 檔案變更權限失敗，Message：%s", path, err))
+		return _err.New(_fmt.Sprintf("無法建立 %s 的暫存檔案，Message：%s", path, err))
 	}
 
-	return nil
+	tmp := file.Name()
+	defer _os.Remove(tmp)
+
+	if err := writeVerified(file, response.Body, meta, response.Headers); err != nil {
+		file.Close()
+		return _err.New(_fmt.Sprintf("%s 檔案寫入失敗，Message：%s", path, err))
+	}
+
+	if err := file.Close(); err != nil {
+		return _err.New(_fmt.Sprintf("%s 檔案寫入失敗，Message：%s", path, err))
+	}
+
+	return place(tmp, path, mode, keepTime, meta.Time)
 }
 func (bucket *Bucket) CopyTo(dest string, args ...interface{}) error {
-	return copy(bucket.s3, bucket, bucket.s3.Bucket(dest))
+	return copy(bucket.s3, bucket, bucket.s3.Bucket(dest).WithContext(bucket.Context()), args...)
 }
 func (bucket *Bucket) CopyFrom(src string, args ...interface{}) error {
-	return copy(bucket.s3, bucket.s3.Bucket(src), bucket)
+	return copy(bucket.s3, bucket.s3.Bucket(src).WithContext(bucket.Context()), bucket, args...)
 }
-func (bucket *Bucket) Clean(goroutines ...uint8) []error {
-	files, err := bucket.Files()
-	if err != nil {
-		return []error{err}
+func (bucket *Bucket) PresignGet(expire _time.Duration) (string, error) {
+	return bucket.presign(_enum.METHOD_GET, expire, "")
+}
+func (bucket *Bucket) PresignPut(expire _time.Duration, contentTypes ...string) (string, error) {
+	contentType := ""
+	if len(contentTypes) > 0 {
+		contentType = contentTypes[0]
+	}
+	return bucket.presign(_enum.METHOD_PUT, expire, contentType)
+}
+func (bucket *Bucket) PresignDelete(expire _time.Duration) (string, error) {
+	return bucket.presign(_enum.METHOD_DELETE, expire, "")
+}
+func (bucket *Bucket) PresignPost(expire _time.Duration, policies ..._Policy) (*_model.PostForm, error) {
+	if bucket == nil {
+		return nil, _err.New("錯誤的 Bucket")
 	}
 
-	gor := 1
-	if len(goroutines) > 0 {
-		gor = int(goroutines[0])
+	prefix := ""
+	if bucket.uri != "" {
+		prefix = _fmt.Sprintf("%s/", bucket.uri)
 	}
 
-	total := len(files)
-	wg := new(_sync.WaitGroup)
-	ins := make(chan string, total)
-	ous := make(chan error, total)
+	acl := _enum.ACL_PRIVATE
+	var contentType *string
+	var minSize, maxSize *uint64
 
-	for i := 0; i < gor; i++ {
-		wg.Add(1)
-		go func(s3 _S3, wg *_sync.WaitGroup, ins <-chan string, ous chan<- error) {
-			defer wg.Done()
+	if len(policies) > 0 && policies[0] != nil {
+		acl = policies[0].AclVal()
+		contentType = policies[0].ContentTypeStr()
+		minSize = policies[0].MinSizeNum()
+		maxSize = policies[0].MaxSizeNum()
+	}
 
-			for in := range ins {
-				if err := s3.Bucket(in).Del(); err != nil {
-					ous <- _err.New(_fmt.Sprintf("刪除檔案 %s 時發生錯誤，Message：%s", in, err))
-				} else {
-					ous <- nil
-				}
-			}
-		}(bucket.s3, wg, ins, ous)
+	fields := map[string]string{
+		"key": _fmt.Sprintf("%s${filename}", prefix),
+		"acl": acl.Str(),
+	}
+	conditions := []interface{}{
+		[]interface{}{"starts-with", "$key", prefix},
+		map[string]string{"acl": acl.Str()},
 	}
 
-	for _, file := range files {
-		ins <- _fmt.Sprintf("%s/%s", bucket.name, file.Key)
+	if contentType != nil {
+		if _str.HasSuffix(*contentType, "/") {
+			conditions = append(conditions, []interface{}{"starts-with", "$Content-Type", *contentType})
+		} else {
+			fields["Content-Type"] = *contentType
+			conditions = append(conditions, []interface{}{"eq", "$Content-Type", *contentType})
+		}
+	}
+
+	if minSize != nil || maxSize != nil {
+		min, max := uint64(0), uint64(5*1024*1024*1024)
+		if minSize != nil {
+			min = *minSize
+		}
+		if maxSize != nil {
+			max = *maxSize
+		}
+		if min > max {
+			return nil, _err.New(_fmt.Sprintf("檔案大小範圍錯誤，最小值 %d 大於最大值 %d", min, max))
+		}
+		conditions = append(conditions, []interface{}{"content-length-range", min, max})
+	}
+
+	if err := bucket.resolveRegion(); err != nil {
+		return nil, err
+	}
+
+	url, fields, err := _req.New(bucket.s3).Bucket(bucket.name).UseSSL(true).PresignPost(expire, conditions, fields)
+	if err != nil {
+		return nil, err
 	}
-	close(ins)
-	wg.Wait()
 
-	errs := []error{}
-	for i := 0; i < total; i++ {
-		if err := <-ous; err != nil {
-			errs = append(errs, err)
+	return &_model.PostForm{Url: url, Fields: fields}, nil
+}
+func (bucket *Bucket) Clean(args ...interface{}) (*_model.Report, error) {
+	if bucket == nil {
+		return &_model.Report{Succeeded: []string{}, Failures: []*_model.Failure{}}, _err.New("錯誤的 Bucket")
+	}
+
+	files, listErr := bucket.Files()
+	if listErr != nil {
+		listErr = _fmt.Errorf("取得檔案列表時發生錯誤，僅清除已取得的 %d 個檔案，Message：%w", len(files), listErr)
+	}
+
+	gor, rate := parseBatch(args)
+	for _, arg := range args {
+		switch val := arg.(type) {
+		case int:
+			gor = val
+		case uint8:
+			gor = int(val)
 		}
 	}
 
-	close(ous)
+	keys := []string{}
+	for _, file := range files {
+		keys = append(keys, file.Key)
+	}
 
-	return errs
+	report, err := bucket.deleteKeys(keys, gor, rate, nil)
+	return report, _err.Join(listErr, err)
 }
@@ -0,0 +1,216 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_context "context"
+	_base64 "encoding/base64"
+	_json "encoding/json"
+	_fmt "fmt"
+	_http "net/http"
+	_httptest "net/http/httptest"
+	_url "net/url"
+	_os "os"
+	_enum "s3/enum"
+	_testing "testing"
+	_time "time"
+)
+
+// 以 httptest 的伺服器取代 S3，路徑為 path-style 的 /bucket/key
+type _Stub struct {
+	scheme string
+	host   string
+}
+
+func (s3 *_Stub) GetS3Interface()                                      {}
+func (s3 *_Stub) Context() _context.Context                            { return _context.Background() }
+func (s3 *_Stub) Signature(str string) string                          { return "" }
+func (s3 *_Stub) SignatureV2(str string) string                        { return "" }
+func (s3 *_Stub) SignatureV4(date, region, service, str string) string { return "" }
+func (s3 *_Stub) AccessKey() string                                    { return "access" }
+func (s3 *_Stub) IsV4() bool                                           { return false }
+func (s3 *_Stub) RegionStr() string                                    { return "us-east-1" }
+func (s3 *_Stub) BucketRegionStr(bucket string) string                 { return "us-east-1" }
+func (s3 *_Stub) HasBucketRegion(bucket string) bool                   { return true }
+func (s3 *_Stub) SetBucketRegion(bucket string, region string)         {}
+func (s3 *_Stub) HttpClient() *_http.Client                            { return nil }
+func (s3 *_Stub) RetryAttempts() uint                                  { return 1 }
+func (s3 *_Stub) RetryDelay(attempt uint) _time.Duration               { return 0 }
+func (s3 *_Stub) SchemeStr() string                                    { return s3.scheme }
+func (s3 *_Stub) HostStr() string                                      { return s3.host }
+func (s3 *_Stub) IsPathStyle() bool                                    { return true }
+func (s3 *_Stub) Bucket(name string) *Bucket {
+	bucket, _ := New(name, s3)
+	return bucket
+}
+
+type _Parts struct {
+	size       uint64
+	goroutines uint8
+	checkpoint string
+}
+
+func (parts _Parts) GetMultipartInterface() {}
+func (parts _Parts) PartSizeNum() *uint64   { return &parts.size }
+func (parts _Parts) GoroutinesNum() *uint8 {
+	if parts.goroutines == 0 {
+		return nil
+	}
+	return &parts.goroutines
+}
+func (parts _Parts) RetryNum() *uint8 { return nil }
+func (parts _Parts) CheckpointStr() *string {
+	if parts.checkpoint == "" {
+		return nil
+	}
+	return &parts.checkpoint
+}
+func (parts _Parts) RateNum() *uint { return nil }
+
+type _Mode _os.FileMode
+
+func (mode _Mode) GetSaveInterface() {}
+func (mode _Mode) ModeVal() *_os.FileMode {
+	val := _os.FileMode(mode)
+	return &val
+}
+func (mode _Mode) IsKeepTime() bool { return false }
+func (mode _Mode) IsResume() bool   { return false }
+
+func stub(t *_testing.T, name string, handler _http.HandlerFunc) *Bucket {
+	server := _httptest.NewServer(handler)
+	t.Cleanup(server.Close)
+
+	url, err := _url.Parse(server.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	bucket, err := New(name, &_Stub{scheme: url.Scheme, host: url.Host})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return bucket
+}
+
+type _PostPolicy struct {
+	contentType string
+	min         uint64
+	max         uint64
+}
+
+func (policy _PostPolicy) GetPolicyInterface() {}
+func (policy _PostPolicy) AclVal() _enum.Acl   { return _enum.ACL_PUBLIC_READ }
+func (policy _PostPolicy) ContentTypeStr() *string {
+	if policy.contentType == "" {
+		return nil
+	}
+	return &policy.contentType
+}
+func (policy _PostPolicy) MinSizeNum() *uint64 { return &policy.min }
+func (policy _PostPolicy) MaxSizeNum() *uint64 { return &policy.max }
+
+func TestPresignPost(t *_testing.T) {
+	bucket := stub(t, "bk/uploads", func(w _http.ResponseWriter, r *_http.Request) {})
+
+	form, err := bucket.PresignPost(_time.Hour, _PostPolicy{contentType: "image/", min: 1, max: 1024})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if form.Fields["key"] != "uploads/${filename}" || form.Fields["acl"] != "public-read" {
+		t.Errorf("欄位錯誤 %v", form.Fields)
+	}
+
+	data, err := _base64.StdEncoding.DecodeString(form.Fields["policy"])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var document struct {
+		Conditions []interface{} `json:"conditions"`
+	}
+	if err := _json.Unmarshal(data, &document); err != nil {
+		t.Fatal(err)
+	}
+
+	found := map[string]bool{}
+	for _, condition := range document.Conditions {
+		found[_fmt.Sprint(condition)] = true
+	}
+	for _, want := range []string{"[starts-with $key uploads/]", "map[acl:public-read]", "[starts-with $Content-Type image/]", "[content-length-range 1 1024]", "map[bucket:bk]"} {
+		if !found[want] {
+			t.Errorf("Policy 缺少條件 %s，內容 %s", want, data)
+		}
+	}
+
+	if _, err := bucket.PresignPost(_time.Hour, _PostPolicy{min: 10, max: 1}); err == nil {
+		t.Error("最小值大於最大值時應該失敗")
+	}
+}
+
+func headerStub(t *_testing.T, name string, method string) (*Bucket, *_http.Header) {
+	sent := &_http.Header{}
+
+	return stub(t, name, func(w _http.ResponseWriter, r *_http.Request) {
+		switch r.Method {
+		case _http.MethodHead:
+			w.Header().Set("Content-Length", "3")
+			w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+			w.Header().Set("ETag", "\"900150983cd24fb0d6963f7d28e17f72\"")
+			w.Header().Set("Content-Type", "image/png")
+		case method:
+			*sent = r.Header.Clone()
+			if r.Header.Get("X-Amz-Copy-Source") != "" {
+				_fmt.Fprint(w, "<CopyObjectResult><ETag>\"etag\"</ETag></CopyObjectResult>")
+			}
+		}
+	}), sent
+}
+
+func TestPutObjectArgs(t *_testing.T) {
+	bucket, sent := headerStub(t, "bk/a.txt", _http.MethodPut)
+
+	if err := bucket.PutString("abc", 60, _enum.STORAGE_STANDARD_IA, _MetaMap{"Owner": "oa"}, _TagsMap{"k": "v w"}); err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]string{"Cache-Control": "max-age=60", "X-Amz-Storage-Class": "STANDARD_IA", "X-Amz-Meta-Owner": "oa", "X-Amz-Tagging": "k=v+w"} {
+		if got := sent.Get(key); got != want {
+			t.Errorf("%s 預期 %q，實際 %q", key, want, got)
+		}
+	}
+}
+
+func TestCopyDirective(t *_testing.T) {
+	bucket, sent := headerStub(t, "bk/src a.txt", _http.MethodPut)
+
+	for _, arg := range []interface{}{60, _Cache("no-cache"), "text/plain", _MetaMap{"owner": "oa"}} {
+		if err := bucket.CopyTo("bk/dest.txt", arg); err == nil {
+			t.Errorf("沒有 DIRECTIVE_REPLACE 時帶入 %v 應該失敗", arg)
+		}
+	}
+
+	if err := bucket.CopyTo("bk/dest.txt", _enum.ACL_PUBLIC_READ, _TagsMap{"k": "v"}); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"X-Amz-Copy-Source": "/bk/src%20a.txt", "X-Amz-Metadata-Directive": "COPY", "X-Amz-Acl": "public-read", "Cache-Control": "", "X-Amz-Tagging": "k=v", "X-Amz-Tagging-Directive": "REPLACE"} {
+		if got := sent.Get(key); got != want {
+			t.Errorf("COPY：%s 預期 %q，實際 %q", key, want, got)
+		}
+	}
+
+	if err := bucket.CopyTo("bk/dest.txt", _enum.DIRECTIVE_REPLACE, 60, _MetaMap{"Owner": "oa"}); err != nil {
+		t.Fatal(err)
+	}
+	for key, want := range map[string]string{"X-Amz-Metadata-Directive": "REPLACE", "Cache-Control": "max-age=60", "Content-Type": "image/png", "X-Amz-Meta-Owner": "oa", "X-Amz-Tagging": ""} {
+		if got := sent.Get(key); got != want {
+			t.Errorf("REPLACE：%s 預期 %q，實際 %q", key, want, got)
+		}
+	}
+}
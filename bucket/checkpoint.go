@@ -0,0 +1,240 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_md5 "crypto/md5"
+	_hex "encoding/hex"
+	_json "encoding/json"
+	_err "errors"
+	_fmt "fmt"
+	_io "io"
+	_ioutil "io/ioutil"
+	_os "os"
+	_fs "path/filepath"
+	_model "s3/model"
+	_sort "sort"
+	_str "strings"
+	_sync "sync"
+	_time "time"
+)
+
+const (
+	CHECKPOINT_EXT    = ".s3checkpoint"
+	CHECKPOINT_SAMPLE = 1024 * 1024
+)
+
+type _Point struct {
+	state *_model.Checkpoint
+	mutex *_sync.Mutex
+}
+
+func fingerprint(path string) (uint64, int64, string, error) {
+	file, err := _os.Open(path)
+	if err != nil {
+		return 0, 0, "", err
+	}
+	defer file.Close()
+
+	stat, err := file.Stat()
+	if err != nil {
+		return 0, 0, "", err
+	}
+
+	h := _md5.New()
+	if _, err := _io.CopyN(h, file, CHECKPOINT_SAMPLE); err != nil && err != _io.EOF {
+		return 0, 0, "", err
+	}
+
+	if stat.Size() > CHECKPOINT_SAMPLE {
+		if _, err := _io.Copy(h, _io.NewSectionReader(file, stat.Size()-CHECKPOINT_SAMPLE, CHECKPOINT_SAMPLE)); err != nil {
+			return 0, 0, "", err
+		}
+	}
+
+	return uint64(stat.Size()), stat.ModTime().UnixNano(), _hex.EncodeToString(h.Sum(nil)), nil
+}
+func checkpointFile(dir string, bucket string, key string, path string) string {
+	hash := _md5.Sum([]byte(_fmt.Sprintf("%s/%s:%s", bucket, key, path)))
+	return _fs.Join(dir, _fmt.Sprintf("%s%s", _hex.EncodeToString(hash[:]), CHECKPOINT_EXT))
+}
+func readCheckpoint(file string) (*_model.Checkpoint, error) {
+	data, err := _ioutil.ReadFile(file)
+	if err != nil {
+		return nil, err
+	}
+
+	var state *_model.Checkpoint = nil
+	if err := _json.Unmarshal(data, &state); err != nil || state == nil {
+		return nil, _err.New(_fmt.Sprintf("檢查點 %s 格式錯誤", file))
+	}
+
+	state.File = file
+	return state, nil
+}
+func writeCheckpoint(state *_model.Checkpoint) error {
+	state.Time = uint64(_time.Now().Unix())
+
+	data, err := _json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	tmp := _fmt.Sprintf("%s.tmp", state.File)
+	if err := _ioutil.WriteFile(tmp, data, 0644); err != nil {
+		return err
+	}
+
+	return _os.Rename(tmp, state.File)
+}
+func isStale(state *_model.Checkpoint) bool {
+	size, modTime, hash, err := fingerprint(state.Path)
+	return err != nil || size != state.Size || modTime != state.ModTime || hash != state.Hash
+}
+
+func (point *_Point) done(number uint) *_model.Part {
+	if point == nil {
+		return nil
+	}
+
+	point.mutex.Lock()
+	defer point.mutex.Unlock()
+
+	for _, part := range point.state.Parts {
+		if part.Number == number {
+			return part
+		}
+	}
+	return nil
+}
+func (point *_Point) save(part *_model.Part) error {
+	if point == nil {
+		return nil
+	}
+
+	point.mutex.Lock()
+	defer point.mutex.Unlock()
+
+	if part != nil {
+		point.state.Parts = append(point.state.Parts, part)
+		_sort.Slice(point.state.Parts, func(i, j int) bool { return point.state.Parts[i].Number < point.state.Parts[j].Number })
+	}
+
+	return writeCheckpoint(point.state)
+}
+func (point *_Point) remove() {
+	if point != nil {
+		_os.Remove(point.state.File)
+	}
+}
+
+func (bucket *Bucket) checkpoint(dir string, path string, size uint64) (*_Point, error) {
+	if err := _os.MkdirAll(dir, 0755); err != nil {
+		return nil, _err.New(_fmt.Sprintf("無法建立檢查點目錄 %s，Message：%s", dir, err))
+	}
+
+	total, modTime, hash, err := fingerprint(path)
+	if err != nil {
+		return nil, _err.New(_fmt.Sprintf("無法取得 %s 檔案的指紋，Message：%s", path, err))
+	}
+
+	file := checkpointFile(dir, bucket.name, bucket.uri, path)
+	fresh := &_model.Checkpoint{Bucket: bucket.name, Key: bucket.uri, Path: path, Size: total, ModTime: modTime, Hash: hash, PartSize: size, Parts: []*_model.Part{}, File: file}
+
+	state, err := readCheckpoint(file)
+	if err != nil {
+		return &_Point{state: fresh, mutex: new(_sync.Mutex)}, nil
+	}
+
+	if state.UploadId == "" || state.Bucket != fresh.Bucket || state.Key != fresh.Key || state.Path != fresh.Path || state.Size != fresh.Size || state.ModTime != fresh.ModTime || state.Hash != fresh.Hash || state.PartSize != fresh.PartSize {
+		if state.UploadId != "" {
+			bucket.AbortMultipart(state.UploadId)
+		}
+		_os.Remove(file)
+		return &_Point{state: fresh, mutex: new(_sync.Mutex)}, nil
+	}
+
+	parts, err := bucket.Parts(state.UploadId)
+	if err != nil && isNotFound(err) {
+		bucket.AbortMultipart(state.UploadId)
+		_os.Remove(file)
+		return &_Point{state: fresh, mutex: new(_sync.Mutex)}, nil
+	}
+
+	if err != nil {
+		return nil, _fmt.Errorf("無法確認檢查點 %s 已上傳的分段，保留檢查點以便續傳，Message：%w", file, err)
+	}
+
+	uploaded := map[uint]*_model.Part{}
+	for _, part := range parts {
+		uploaded[part.Number] = part
+	}
+
+	fresh.UploadId = state.UploadId
+	for _, part := range state.Parts {
+		if remote, ok := uploaded[part.Number]; ok && remote.ETag == part.ETag && remote.Size == part.Size {
+			fresh.Parts = append(fresh.Parts, part)
+		}
+	}
+
+	return &_Point{state: fresh, mutex: new(_sync.Mutex)}, nil
+}
+
+func Checkpoints(dir string) ([]*_model.Checkpoint, error) {
+	states := []*_model.Checkpoint{}
+
+	files, err := _ioutil.ReadDir(dir)
+	if err != nil {
+		if _os.IsNotExist(err) {
+			return states, nil
+		}
+		return states, _err.New(_fmt.Sprintf("無法讀取檢查點目錄 %s，Message：%s", dir, err))
+	}
+
+	for _, file := range files {
+		if file.IsDir() || !_str.HasSuffix(file.Name(), CHECKPOINT_EXT) {
+			continue
+		}
+
+		state, err := readCheckpoint(_fs.Join(dir, file.Name()))
+		if err != nil {
+			return states, err
+		}
+		states = append(states, state)
+	}
+
+	return states, nil
+}
+func CleanCheckpoints(s3 _S3, dir string, expire _time.Duration) ([]*_model.Checkpoint, error) {
+	cleans := []*_model.Checkpoint{}
+
+	states, err := Checkpoints(dir)
+	if err != nil {
+		return cleans, err
+	}
+
+	for _, state := range states {
+		if _time.Since(_time.Unix(int64(state.Time), 0)) < expire && !isStale(state) {
+			continue
+		}
+
+		if state.UploadId != "" && state.Key != "" {
+			if err := s3.Bucket(_fmt.Sprintf("%s/%s", state.Bucket, state.Key)).AbortMultipart(state.UploadId); err != nil && !isNotFound(err) {
+				return cleans, _fmt.Errorf("取消分段上傳 %s 失敗，Message：%w", state.UploadId, err)
+			}
+		}
+
+		if err := _os.Remove(state.File); err != nil && !_os.IsNotExist(err) {
+			return cleans, _err.New(_fmt.Sprintf("無法刪除檢查點 %s，Message：%s", state.File, err))
+		}
+
+		cleans = append(cleans, state)
+	}
+
+	return cleans, nil
+}
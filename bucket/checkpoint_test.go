@@ -0,0 +1,196 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_bytes "bytes"
+	_md5 "crypto/md5"
+	_fmt "fmt"
+	_io "io"
+	_http "net/http"
+	_os "os"
+	_fs "path/filepath"
+	_strconv "strconv"
+	_sync "sync"
+	_testing "testing"
+)
+
+// 最小的分段上傳伺服器，deny 的分段回應 403，listStatus 不為 0 時 ListParts 以該狀態回應
+type _Uploads struct {
+	mutex      _sync.Mutex
+	uploads    map[string]map[int][]byte
+	objects    map[string][]byte
+	created    int
+	parts      []int
+	aborted    []string
+	deny       int
+	listStatus int
+}
+
+func newUploads() *_Uploads {
+	return &_Uploads{uploads: map[string]map[int][]byte{}, objects: map[string][]byte{}}
+}
+func (server *_Uploads) ServeHTTP(w _http.ResponseWriter, r *_http.Request) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	body, _ := _io.ReadAll(r.Body)
+	query := r.URL.Query()
+
+	if _, ok := query["uploads"]; ok && r.Method == _http.MethodPost {
+		server.created++
+		id := _fmt.Sprintf("upload-%d", server.created)
+		server.uploads[id] = map[int][]byte{}
+		_fmt.Fprintf(w, "<InitiateMultipartUploadResult><Bucket>bk</Bucket><Key>%s</Key><UploadId>%s</UploadId></InitiateMultipartUploadResult>", r.URL.Path, id)
+		return
+	}
+
+	id := query.Get("uploadId")
+	upload, ok := server.uploads[id]
+	if id != "" && !ok {
+		w.WriteHeader(_http.StatusNotFound)
+		_fmt.Fprint(w, "<Error><Code>NoSuchUpload</Code></Error>")
+		return
+	}
+
+	switch {
+	case id != "" && r.Method == _http.MethodPut:
+		number, _ := _strconv.Atoi(query.Get("partNumber"))
+		if number == server.deny {
+			w.WriteHeader(_http.StatusForbidden)
+			_fmt.Fprint(w, "<Error><Code>AccessDenied</Code></Error>")
+			return
+		}
+		server.parts = append(server.parts, number)
+		upload[number] = body
+		w.Header().Set("ETag", _fmt.Sprintf("\"%x\"", _md5.Sum(body)))
+
+	case id != "" && r.Method == _http.MethodGet:
+		if server.listStatus != 0 {
+			w.WriteHeader(server.listStatus)
+			_fmt.Fprint(w, "<Error><Code>ServiceUnavailable</Code></Error>")
+			return
+		}
+		_fmt.Fprint(w, "<ListPartsResult><IsTruncated>false</IsTruncated>")
+		for number := 1; number <= len(upload)+1; number++ {
+			if data, ok := upload[number]; ok {
+				_fmt.Fprintf(w, "<Part><PartNumber>%d</PartNumber><LastModified>2020-01-01T00:00:00.000Z</LastModified><ETag>\"%x\"</ETag><Size>%d</Size></Part>", number, _md5.Sum(data), len(data))
+			}
+		}
+		_fmt.Fprint(w, "</ListPartsResult>")
+
+	case id != "" && r.Method == _http.MethodPost:
+		data := []byte{}
+		for number := 1; number <= len(upload); number++ {
+			data = append(data, upload[number]...)
+		}
+		server.objects[r.URL.Path] = data
+		delete(server.uploads, id)
+		_fmt.Fprint(w, "<CompleteMultipartUploadResult><ETag>\"done-3\"</ETag></CompleteMultipartUploadResult>")
+
+	case id != "" && r.Method == _http.MethodDelete:
+		server.aborted = append(server.aborted, id)
+		delete(server.uploads, id)
+		w.WriteHeader(_http.StatusNoContent)
+	}
+}
+
+func checkpointFixture(t *_testing.T) (*_Uploads, *Bucket, string, []byte, _Parts) {
+	server := newUploads()
+	bucket := stub(t, "bk/big.bin", server.ServeHTTP)
+
+	data := _bytes.Repeat([]byte("0123456789"), (2*PART_MIN_SIZE+PART_MIN_SIZE/2)/10)
+	path := _fs.Join(t.TempDir(), "big.bin")
+	if err := _os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	return server, bucket, path, data, _Parts{size: PART_MIN_SIZE, goroutines: 1, checkpoint: t.TempDir()}
+}
+
+func TestCheckpointResume(t *_testing.T) {
+	server, bucket, path, data, parts := checkpointFixture(t)
+
+	server.deny = 2
+	if err := bucket.Upload(path, parts); err == nil {
+		t.Fatal("分段 2 被拒絕時應該失敗")
+	}
+
+	states, err := Checkpoints(parts.checkpoint)
+	if err != nil || len(states) != 1 || len(states[0].Parts) == 0 || len(server.aborted) != 0 {
+		t.Fatalf("失敗後應保留檢查點且不取消上傳，檢查點 %v，取消 %v，錯誤 %v", states, server.aborted, err)
+	}
+
+	server.deny, server.parts = 0, nil
+	if err := bucket.Upload(path, parts); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, number := range server.parts {
+		if number == 1 {
+			t.Errorf("續傳時不應重新上傳分段 1，實際上傳 %v", server.parts)
+		}
+	}
+
+	if server.created != 1 || !_bytes.Equal(server.objects["/bk/big.bin"], data) {
+		t.Errorf("續傳應沿用同一個上傳，建立 %d 次", server.created)
+	}
+
+	if states, _ := Checkpoints(parts.checkpoint); len(states) != 0 {
+		t.Errorf("完成後應刪除檢查點，剩下 %d 個", len(states))
+	}
+}
+func TestCheckpointInvalidation(t *_testing.T) {
+	server, bucket, path, data, parts := checkpointFixture(t)
+
+	server.deny = 2
+	bucket.Upload(path, parts)
+
+	data[0] = 'x'
+	if err := _os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	server.deny = 0
+	if err := bucket.Upload(path, parts); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(server.aborted) != 1 || server.aborted[0] != "upload-1" || server.created != 2 {
+		t.Errorf("檔案改變後應取消舊的上傳並重新建立，取消 %v，建立 %d 次", server.aborted, server.created)
+	}
+
+	if !_bytes.Equal(server.objects["/bk/big.bin"], data) {
+		t.Error("上傳的內容應為修改後的檔案")
+	}
+}
+func TestCheckpointPartsError(t *_testing.T) {
+	server, bucket, path, _, parts := checkpointFixture(t)
+
+	server.deny = 2
+	bucket.Upload(path, parts)
+
+	server.deny, server.listStatus = 0, _http.StatusServiceUnavailable
+	if err := bucket.Upload(path, parts); err == nil {
+		t.Fatal("無法取得已上傳的分段時應該失敗")
+	}
+
+	if states, _ := Checkpoints(parts.checkpoint); len(states) != 1 || server.created != 1 || len(server.aborted) != 0 {
+		t.Fatalf("暫時性錯誤應保留檢查點，檢查點 %d 個，建立 %d 次，取消 %v", len(states), server.created, server.aborted)
+	}
+
+	server.listStatus = 0
+	delete(server.uploads, "upload-1")
+	if err := bucket.Upload(path, parts); err != nil {
+		t.Fatal(err)
+	}
+
+	if server.created != 2 {
+		t.Errorf("上傳已不存在時應重新建立，建立 %d 次", server.created)
+	}
+}
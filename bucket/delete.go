@@ -0,0 +1,168 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_context "context"
+	_md5 "crypto/md5"
+	_base64 "encoding/base64"
+	_xml "encoding/xml"
+	_err "errors"
+	_fmt "fmt"
+	_enum "s3/enum"
+	_model "s3/model"
+	_req "s3/request"
+	_resp "s3/request/response"
+	_sync "sync"
+)
+
+const (
+	DELETE_MAX_NUM = 1000
+)
+
+func (bucket *Bucket) deleteBatch(keys []string, verbose bool, report *_model.Report) error {
+	type _Object struct {
+		Key string `xml:"Key"`
+	}
+
+	objects := []_Object{}
+	for _, key := range keys {
+		objects = append(objects, _Object{Key: key})
+	}
+
+	encoder, err := _xml.MarshalIndent(struct {
+		XMLName _xml.Name `xml:"Delete"`
+		Quiet   bool      `xml:"Quiet"`
+		Objects []_Object `xml:"Object"`
+	}{Quiet: !verbose, Objects: objects}, "", "  ")
+
+	if err != nil {
+		return _err.New(_fmt.Sprintf("產生 XML 失敗，Message：%s", err))
+	}
+
+	body := _fmt.Sprintf("%s%s", _xml.Header, string(encoder))
+	sum := _md5.Sum([]byte(body))
+
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Method(_enum.METHOD_POST).Parameter("delete", "").SetXML(body).SetHeader("Content-MD5", _base64.StdEncoding.EncodeToString(sum[:])).Response()
+	if err := response.IsSuccess(); err != nil {
+		return err
+	}
+
+	if err := errorOfBody(response.StatusCode, response.BodyBytes); err != nil {
+		return err
+	}
+
+	var result *struct {
+		Deleted []struct {
+			Key string `xml:"Key"`
+		} `xml:"Deleted"`
+		Errors []struct {
+			Key     string `xml:"Key"`
+			Code    string `xml:"Code"`
+			Message string `xml:"Message"`
+		} `xml:"Error"`
+	} = nil
+
+	if err := _xml.Unmarshal(response.BodyBytes, &result); err != nil {
+		return _err.New(_fmt.Sprintf("編譯 XML 失敗，Message：%s", err))
+	}
+
+	failed := map[string]bool{}
+	for _, item := range result.Errors {
+		failed[item.Key] = true
+		report.Failures = append(report.Failures, &_model.Failure{Key: item.Key, Error: &_resp.Error{StatusCode: response.StatusCode, Code: item.Code, Message: item.Message, Resource: item.Key}})
+	}
+
+	if verbose {
+		for _, deleted := range result.Deleted {
+			report.Succeeded = append(report.Succeeded, deleted.Key)
+		}
+	} else {
+		for _, key := range keys {
+			if !failed[key] {
+				report.Succeeded = append(report.Succeeded, key)
+			}
+		}
+	}
+
+	report.Success += uint64(len(keys) - len(result.Errors))
+	return nil
+}
+
+func (bucket *Bucket) DeleteMany(keys []string, verbose ...bool) (*_model.Report, error) {
+	report := &_model.Report{Total: uint64(len(keys)), Succeeded: []string{}, Failures: []*_model.Failure{}}
+
+	if bucket == nil {
+		return report, _err.New("錯誤的 Bucket")
+	}
+
+	isVerbose := len(verbose) > 0 && verbose[0]
+	var failed error
+
+	for start := 0; start < len(keys); start += DELETE_MAX_NUM {
+		end := start + DELETE_MAX_NUM
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		if err := bucket.deleteBatch(keys[start:end], isVerbose, report); err != nil {
+			for _, key := range keys[start:end] {
+				report.Failures = append(report.Failures, &_model.Failure{Key: key, Error: err})
+			}
+			if failed == nil {
+				failed = err
+			}
+			if bucket.Context().Err() != nil {
+				return report, bucket.Context().Err()
+			}
+		}
+	}
+
+	return report, failed
+}
+func (bucket *Bucket) deleteKeys(keys []string, gor int, rate uint, progress func(done uint64, total uint64)) (*_model.Report, error) {
+	report := &_model.Report{Total: uint64(len(keys)), Succeeded: []string{}, Failures: []*_model.Failure{}}
+	pool := newPool(bucket.Context(), gor, rate, false)
+	mutex := new(_sync.Mutex)
+	done := uint64(0)
+
+	for start := 0; start < len(keys); start += DELETE_MAX_NUM {
+		end := start + DELETE_MAX_NUM
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		batch := keys[start:end]
+		if !pool.Go(func(ctx _context.Context) error {
+			result, _ := bucket.WithContext(ctx).DeleteMany(batch)
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			errs := []error{}
+			for _, failure := range result.Failures {
+				errs = append(errs, _fmt.Errorf("刪除檔案 %s 時發生錯誤，Message：%w", failure.Key, failure.Error))
+			}
+
+			report.Success += result.Success
+			report.Succeeded = append(report.Succeeded, result.Succeeded...)
+			report.Failures = append(report.Failures, result.Failures...)
+
+			done += uint64(len(batch))
+			if progress != nil {
+				progress(done, report.Total)
+			}
+
+			return _err.Join(errs...)
+		}) {
+			break
+		}
+	}
+
+	return report, _err.Join(pool.Wait(), bucket.Context().Err())
+}
@@ -0,0 +1,126 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_md5 "crypto/md5"
+	_base64 "encoding/base64"
+	_xml "encoding/xml"
+	_err "errors"
+	_fmt "fmt"
+	_io "io"
+	_http "net/http"
+	_resp "s3/request/response"
+	_sort "sort"
+	_sync "sync"
+	_testing "testing"
+)
+
+// 最小的 Multi-Object Delete 伺服器，deny 內的 Key 回應 AccessDenied
+type _Deletes struct {
+	t        *_testing.T
+	mutex    _sync.Mutex
+	deny     map[string]bool
+	requests int
+	quiet    []bool
+}
+
+func (server *_Deletes) ServeHTTP(w _http.ResponseWriter, r *_http.Request) {
+	server.mutex.Lock()
+	defer server.mutex.Unlock()
+
+	body, _ := _io.ReadAll(r.Body)
+	sum := _md5.Sum(body)
+
+	if _, ok := r.URL.Query()["delete"]; !ok || r.Method != _http.MethodPost {
+		server.t.Errorf("錯誤的請求 %s %s", r.Method, r.URL)
+	}
+	if r.Header.Get("Content-MD5") != _base64.StdEncoding.EncodeToString(sum[:]) {
+		server.t.Errorf("Content-MD5 錯誤 %s", r.Header.Get("Content-MD5"))
+	}
+
+	var document struct {
+		Quiet   bool `xml:"Quiet"`
+		Objects []struct {
+			Key string `xml:"Key"`
+		} `xml:"Object"`
+	}
+	if err := _xml.Unmarshal(body, &document); err != nil {
+		server.t.Fatal(err)
+	}
+
+	server.requests++
+	server.quiet = append(server.quiet, document.Quiet)
+
+	_fmt.Fprint(w, "<DeleteResult>")
+	for _, object := range document.Objects {
+		if server.deny[object.Key] {
+			_fmt.Fprintf(w, "<Error><Key>%s</Key><Code>AccessDenied</Code><Message>Access Denied</Message></Error>", object.Key)
+		} else if !document.Quiet {
+			_fmt.Fprintf(w, "<Deleted><Key>%s</Key></Deleted>", object.Key)
+		}
+	}
+	_fmt.Fprint(w, "</DeleteResult>")
+}
+
+func TestDeleteMany(t *_testing.T) {
+	server := &_Deletes{t: t, deny: map[string]bool{"b": true}}
+	bucket := stub(t, "bk", server.ServeHTTP)
+
+	for _, verbose := range []bool{false, true} {
+		report, err := bucket.DeleteMany([]string{"a", "b", "c"}, verbose)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if report.Total != 3 || report.Success != 2 || _fmt.Sprint(report.Succeeded) != "[a c]" {
+			t.Errorf("verbose %v：結果錯誤 %+v", verbose, report)
+		}
+
+		if len(report.Failures) != 1 || report.Failures[0].Key != "b" {
+			t.Fatalf("verbose %v：失敗項目錯誤 %+v", verbose, report.Failures)
+		}
+
+		var failure *_resp.Error
+		if !_err.As(report.Failures[0].Error, &failure) || failure.Code != "AccessDenied" {
+			t.Errorf("verbose %v：錯誤內容 %v", verbose, report.Failures[0].Error)
+		}
+	}
+
+	if _fmt.Sprint(server.quiet) != "[true false]" {
+		t.Errorf("Quiet 錯誤 %v", server.quiet)
+	}
+}
+
+func TestDeleteKeys(t *_testing.T) {
+	server := &_Deletes{t: t, deny: map[string]bool{"key-1200": true}}
+	bucket := stub(t, "bk", server.ServeHTTP)
+
+	keys := []string{}
+	for i := 0; i < DELETE_MAX_NUM+DELETE_MAX_NUM/2; i++ {
+		keys = append(keys, _fmt.Sprintf("key-%d", i))
+	}
+
+	report, err := bucket.deleteKeys(keys, 2, 0, nil)
+	if err == nil {
+		t.Error("有檔案刪除失敗時應該回傳錯誤")
+	}
+
+	if server.requests != 2 {
+		t.Errorf("應該分 2 次請求，實際 %d", server.requests)
+	}
+
+	if report.Success != uint64(len(keys)-1) || len(report.Succeeded) != len(keys)-1 || len(report.Failures) != 1 {
+		t.Errorf("結果錯誤 Success %d，Succeeded %d，Failures %d", report.Success, len(report.Succeeded), len(report.Failures))
+	}
+
+	_sort.Strings(report.Succeeded)
+	if idx := _sort.SearchStrings(report.Succeeded, "key-1200"); idx < len(report.Succeeded) && report.Succeeded[idx] == "key-1200" {
+		t.Error("失敗的檔案不應該列在 Succeeded")
+	}
+}
@@ -0,0 +1,513 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_context "context"
+	_md5 "crypto/md5"
+	_sha1 "crypto/sha1"
+	_sha256 "crypto/sha256"
+	_base64 "encoding/base64"
+	_hex "encoding/hex"
+	_json "encoding/json"
+	_err "errors"
+	_fmt "fmt"
+	_hash "hash"
+	_crc32 "hash/crc32"
+	_io "io"
+	_ioutil "io/ioutil"
+	_os "os"
+	_fs "path/filepath"
+	_regx "regexp"
+	_enum "s3/enum"
+	_model "s3/model"
+	_req "s3/request"
+	_strconv "strconv"
+	_str "strings"
+	_time "time"
+)
+
+const (
+	RESUME_EXT = ".s3resume"
+	TEMP_EXT   = ".s3tmp"
+)
+
+type _Range struct {
+	Offset int64
+	Length int64
+}
+type _Resume struct {
+	ETag string
+	Time uint64
+	Size uint64
+}
+type _Writer struct {
+	file   *_os.File
+	offset int64
+}
+
+func (writer *_Writer) Write(data []byte) (int, error) {
+	n, err := writer.file.WriteAt(data, writer.offset)
+	writer.offset += int64(n)
+	return n, err
+}
+
+func rangeHeader(offset int64, length int64) (string, error) {
+	switch {
+	case offset < 0 && length >= 0:
+		return "", _err.New("取最後一段資料時，長度需小於 0")
+	case offset < 0:
+		return _fmt.Sprintf("bytes=%d", offset), nil
+	case length == 0:
+		return "", _err.New("範圍長度不可為 0")
+	case length < 0:
+		return _fmt.Sprintf("bytes=%d-", offset), nil
+	default:
+		return _fmt.Sprintf("bytes=%d-%d", offset, offset+length-1), nil
+	}
+}
+func parseContentRange(val string) (*_model.ContentRange, error) {
+	matches := _regx.MustCompile(`^bytes (\d+)-(\d+)/(\d+)$`).FindStringSubmatch(_str.TrimSpace(val))
+	if len(matches) != 4 {
+		return nil, _err.New(_fmt.Sprintf("Content-Range 格式有誤，其值 %s", val))
+	}
+
+	nums := []uint64{}
+	for _, match := range matches[1:] {
+		num, err := _strconv.ParseUint(match, 10, 64)
+		if err != nil {
+			return nil, _err.New(_fmt.Sprintf("Content-Range 格式有誤，Message：%s", err))
+		}
+		nums = append(nums, num)
+	}
+
+	if nums[0] > nums[1] || nums[1] >= nums[2] {
+		return nil, _err.New(_fmt.Sprintf("Content-Range 範圍有誤，其值 %s", val))
+	}
+
+	return &_model.ContentRange{Start: nums[0], End: nums[1], Total: nums[2]}, nil
+}
+func readResume(path string) *_Resume {
+	data, err := _ioutil.ReadFile(path + RESUME_EXT)
+	if err != nil {
+		return nil
+	}
+
+	var resume *_Resume = nil
+	if err := _json.Unmarshal(data, &resume); err != nil || resume == nil || resume.ETag == "" {
+		return nil
+	}
+	return resume
+}
+func writeResume(path string, resume *_Resume) error {
+	data, err := _json.Marshal(resume)
+	if err != nil {
+		return err
+	}
+	return _ioutil.WriteFile(path+RESUME_EXT, data, 0644)
+}
+func isMD5ETag(etag string) bool {
+	return _regx.MustCompile(`^[0-9a-f]{32}$`).MatchString(etag)
+}
+func getFileMD5Hex(path string) (string, error) {
+	file, err := _os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+
+	h := _md5.New()
+	if _, err := _io.Copy(h, file); err != nil {
+		return "", err
+	}
+	return _hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func parseSave(args []interface{}) (_os.FileMode, bool, bool) {
+	var mode _os.FileMode = 0644
+	keepTime, resume := false, false
+
+	for _, arg := range args {
+		switch val := arg.(type) {
+		case _os.FileMode:
+			mode = val
+		case int:
+			mode = _os.FileMode(val)
+		case _Save:
+			if val == nil {
+				continue
+			}
+			if m := val.ModeVal(); m != nil {
+				mode = *m
+			}
+			keepTime = val.IsKeepTime()
+			resume = val.IsResume()
+		}
+	}
+
+	return mode, keepTime, resume
+}
+func checksumOf(meta *_model.FileMeta, headers map[string]string) (_hash.Hash, string, func([]byte) string) {
+	if meta.IsMd5 {
+		return _md5.New(), meta.Md5, _hex.EncodeToString
+	}
+
+	checksums := []struct {
+		Key string
+		New func() _hash.Hash
+	}{
+		{"X-Amz-Checksum-Sha256", _sha256.New},
+		{"X-Amz-Checksum-Sha1", _sha1.New},
+		{"X-Amz-Checksum-Crc32", func() _hash.Hash { return _crc32.NewIEEE() }},
+		{"X-Amz-Checksum-Crc32c", func() _hash.Hash { return _crc32.New(_crc32.MakeTable(_crc32.Castagnoli)) }},
+	}
+
+	for _, checksum := range checksums {
+		if val, ok := headers[checksum.Key]; ok && val != "" && !_str.Contains(val, "-") {
+			return checksum.New(), val, _base64.StdEncoding.EncodeToString
+		}
+	}
+
+	return nil, "", nil
+}
+func writeVerified(file *_os.File, body _io.Reader, meta *_model.FileMeta, headers map[string]string) error {
+	h, expect, encode := checksumOf(meta, headers)
+
+	var writer _io.Writer = file
+	if h != nil {
+		writer = _io.MultiWriter(file, h)
+	}
+
+	n, err := _io.Copy(writer, body)
+	if err != nil {
+		return err
+	}
+
+	if uint64(n) != meta.ContentLength {
+		return _err.New(_fmt.Sprintf("資料不完整，預期 %d，實際 %d", meta.ContentLength, n))
+	}
+
+	if h != nil && encode(h.Sum(nil)) != expect {
+		return _err.New(_fmt.Sprintf("檢查碼不符，預期 %s，實際 %s", expect, encode(h.Sum(nil))))
+	}
+
+	return file.Sync()
+}
+func place(tmp string, path string, mode _os.FileMode, keepTime bool, stamp uint64) error {
+	if err := _os.Chmod(tmp, mode); err != nil {
+		return _err.New(_fmt.Sprintf("%s 檔案變更權限失敗，Message：%s", path, err))
+	}
+
+	if keepTime {
+		time := _time.Unix(int64(stamp), 0)
+		if err := _os.Chtimes(tmp, time, time); err != nil {
+			return _err.New(_fmt.Sprintf("%s 檔案變更時間失敗，Message：%s", path, err))
+		}
+	}
+
+	if err := _os.Rename(tmp, path); err != nil {
+		return _err.New(_fmt.Sprintf("%s 檔案搬移失敗，Message：%s", path, err))
+	}
+
+	syncDir(_fs.Dir(path))
+	return nil
+}
+func syncDir(dir string) {
+	if file, err := _os.Open(dir); err == nil {
+		file.Sync()
+		file.Close()
+	}
+}
+
+func (bucket *Bucket) streamRange(offset int64, length int64, etag string) (_io.ReadCloser, *_model.FileMeta, *_model.ContentRange, error) {
+	if bucket == nil {
+		return nil, nil, nil, _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return nil, nil, nil, _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	header, err := rangeHeader(offset, length)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_GET).SetHeader("Range", header).SetHeader("If-Match", etag).Stream(true).Response()
+
+	if err := response.IsSuccess([]uint16{200, 206}); err != nil {
+		if response.Body != nil {
+			response.Body.Close()
+		}
+		return nil, nil, nil, err
+	}
+
+	meta, err := parseMeta(response.Headers)
+	if err != nil {
+		response.Body.Close()
+		return nil, nil, nil, err
+	}
+
+	if val, ok := response.Headers["Content-Range"]; ok {
+		contentRange, err := parseContentRange(val)
+		if err != nil {
+			response.Body.Close()
+			return nil, nil, nil, err
+		}
+		return response.Body, meta, contentRange, nil
+	}
+
+	if meta.ContentLength == 0 {
+		response.Body.Close()
+		return nil, nil, nil, _err.New("檔案為空，無法取得指定範圍")
+	}
+
+	return response.Body, meta, &_model.ContentRange{Start: 0, End: meta.ContentLength - 1, Total: meta.ContentLength}, nil
+}
+func (bucket *Bucket) downloadRange(file *_os.File, job _Range, etag string, retry int) error {
+	var err error
+	for i := 0; i < retry || i == 0; i++ {
+		if err = bucket.writeRange(file, job, etag); err == nil {
+			return nil
+		}
+		if bucket.Context().Err() != nil {
+			break
+		}
+	}
+	return _fmt.Errorf("下載範圍 %d-%d 失敗，Message：%w", job.Offset, job.Offset+job.Length-1, err)
+}
+func (bucket *Bucket) writeRange(file *_os.File, job _Range, etag string) error {
+	body, _, contentRange, err := bucket.streamRange(job.Offset, job.Length, etag)
+	if err != nil {
+		return err
+	}
+	defer body.Close()
+
+	if contentRange.Start != uint64(job.Offset) || contentRange.End != uint64(job.Offset+job.Length-1) {
+		return _err.New(_fmt.Sprintf("回傳範圍 %d-%d 與請求不符", contentRange.Start, contentRange.End))
+	}
+
+	n, err := _io.Copy(&_Writer{file: file, offset: job.Offset}, _io.LimitReader(body, job.Length))
+	if err != nil {
+		return err
+	}
+	if n != job.Length {
+		return _err.New(_fmt.Sprintf("範圍資料不完整，預期 %d，實際 %d", job.Length, n))
+	}
+	return nil
+}
+
+func (bucket *Bucket) StreamRange(offset int64, length int64) (_io.ReadCloser, *_model.FileMeta, *_model.ContentRange, error) {
+	return bucket.streamRange(offset, length, "")
+}
+func (bucket *Bucket) FileRange(offset int64, length int64) ([]byte, *_model.ContentRange, error) {
+	body, _, contentRange, err := bucket.StreamRange(offset, length)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer body.Close()
+
+	data, err := _ioutil.ReadAll(body)
+	if err != nil {
+		return nil, nil, _err.New(_fmt.Sprintf("讀取範圍資料失敗，Message：%s", err))
+	}
+
+	return data, contentRange, nil
+}
+func (bucket *Bucket) Download(path string, args ...interface{}) error {
+	if bucket == nil {
+		return _err.New("錯誤的 Bucket")
+	}
+
+	meta, err := bucket.Meta()
+	if err != nil {
+		return err
+	}
+
+	size, gor, retry := parseMultipart(args)
+	if size < PART_MIN_SIZE {
+		size = PART_MIN_SIZE
+	}
+	if meta.ContentLength <= size {
+		return bucket.Save(path, args...)
+	}
+
+	path, err = _fs.Abs(path)
+	if err != nil {
+		return _err.New(_fmt.Sprintf("無法取得 %s 檔案的絕對位置，Message：%s", path, err))
+	}
+
+	mode, keepTime, _ := parseSave(args)
+
+	file, err := _ioutil.TempFile(_fs.Dir(path), _fmt.Sprintf(".%s.*%s", _fs.Base(path), TEMP_EXT))
+	if err != nil {
+		return _err.New(_fmt.Sprintf("無法建立 %s 的暫存檔案，Message：%s", path, err))
+	}
+
+	tmp := file.Name()
+	defer _os.Remove(tmp)
+
+	if err := file.Truncate(int64(meta.ContentLength)); err != nil {
+		file.Close()
+		return _err.New(_fmt.Sprintf("%s 檔案配置空間失敗，Message：%s", path, err))
+	}
+
+	etag := ""
+	if meta.Md5 != "" {
+		etag = _fmt.Sprintf("\"%s\"", meta.Md5)
+	}
+
+	pool := newPool(bucket.Context(), gor, parseRate(args), true)
+
+	for offset := int64(0); offset < int64(meta.ContentLength); offset += int64(size) {
+		job := _Range{Offset: offset, Length: int64(size)}
+		if offset+job.Length > int64(meta.ContentLength) {
+			job.Length = int64(meta.ContentLength) - offset
+		}
+
+		if !pool.Go(func(ctx _context.Context) error {
+			return bucket.WithContext(ctx).downloadRange(file, job, etag, retry)
+		}) {
+			break
+		}
+	}
+
+	failed := pool.Wait()
+	if failed == nil && bucket.Context().Err() != nil {
+		failed = bucket.Context().Err()
+	}
+
+	if failed == nil {
+		if err := file.Sync(); err != nil {
+			failed = _err.New(_fmt.Sprintf("%s 檔案寫入失敗，Message：%s", path, err))
+		}
+	}
+	file.Close()
+
+	if failed != nil {
+		return _fmt.Errorf("分段下載失敗，Message：%w", failed)
+	}
+
+	if stat, err := _os.Stat(tmp); err != nil || uint64(stat.Size()) != meta.ContentLength {
+		return _err.New(_fmt.Sprintf("%s 檔案大小與 S3 不符", path))
+	}
+
+	if meta.IsMd5 {
+		if md5, err := getFileMD5Hex(tmp); err != nil || md5 != meta.Md5 {
+			return _err.New(_fmt.Sprintf("%s 檔案的 MD5 與 S3 的 ETag 不符", path))
+		}
+	}
+
+	return place(tmp, path, mode, keepTime, meta.Time)
+}
+func (bucket *Bucket) saveResume(path string, mode _os.FileMode, keepTime bool) error {
+	partial := path + TEMP_EXT
+
+	offset := int64(0)
+	resume := readResume(path)
+	if stat, err := _os.Stat(partial); err == nil && stat.Mode().IsRegular() && resume != nil && uint64(stat.Size()) < resume.Size {
+		offset = stat.Size()
+	}
+
+	request := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_GET).SetHeader("Accept-Encoding", "identity").SetAmzHeader("x-amz-checksum-mode", "ENABLED").Stream(true)
+	if offset > 0 {
+		request = request.SetHeader("Range", _fmt.Sprintf("bytes=%d-", offset)).SetHeader("If-Range", _fmt.Sprintf("\"%s\"", resume.ETag))
+	}
+
+	response := request.Response()
+	if offset > 0 && response.StatusCode == 416 {
+		if response.Body != nil {
+			response.Body.Close()
+		}
+		_os.Remove(partial)
+		_os.Remove(path + RESUME_EXT)
+		return bucket.saveResume(path, mode, keepTime)
+	}
+
+	if err := response.IsSuccess([]uint16{200, 206}); err != nil {
+		if response.Body != nil {
+			response.Body.Close()
+		}
+		return err
+	}
+	defer response.Body.Close()
+
+	meta, err := parseMeta(response.Headers)
+	if err != nil {
+		return err
+	}
+
+	var file *_os.File
+	if response.StatusCode == 206 {
+		contentRange, err := parseContentRange(response.Headers["Content-Range"])
+		if err != nil {
+			return err
+		}
+		if contentRange.Start != uint64(offset) || contentRange.Total != resume.Size || meta.Md5 != resume.ETag {
+			_os.Remove(partial)
+			_os.Remove(path + RESUME_EXT)
+			return _err.New(_fmt.Sprintf("%s 續傳範圍與 S3 檔案不符，請重新下載", path))
+		}
+
+		if file, err = _os.OpenFile(partial, _os.O_WRONLY|_os.O_APPEND, 0644); err != nil {
+			return _err.New(_fmt.Sprintf("無法取得 %s 檔案的資源，Message：%s", partial, err))
+		}
+
+		n, err := _io.Copy(file, response.Body)
+		if err == nil && uint64(n) != meta.ContentLength {
+			err = _err.New(_fmt.Sprintf("資料不完整，預期 %d，實際 %d", meta.ContentLength, n))
+		}
+		if err == nil {
+			err = file.Sync()
+		}
+		if err != nil {
+			file.Close()
+			return _err.New(_fmt.Sprintf("%s 檔案寫入失敗，可再次呼叫續傳，Message：%s", path, err))
+		}
+
+		meta.ContentLength = resume.Size
+	} else {
+		if meta.Md5 != "" {
+			if err := writeResume(path, &_Resume{ETag: meta.Md5, Time: meta.Time, Size: meta.ContentLength}); err != nil {
+				return _err.New(_fmt.Sprintf("無法寫入 %s 的續傳資訊，Message：%s", path, err))
+			}
+		}
+
+		if file, err = _os.OpenFile(partial, _os.O_WRONLY|_os.O_CREATE|_os.O_TRUNC, 0644); err != nil {
+			return _err.New(_fmt.Sprintf("無法取得 %s 檔案的資源，Message：%s", partial, err))
+		}
+
+		if err := writeVerified(file, response.Body, meta, response.Headers); err != nil {
+			file.Close()
+			return _err.New(_fmt.Sprintf("%s 檔案寫入失敗，可再次呼叫續傳，Message：%s", path, err))
+		}
+	}
+
+	if err := file.Close(); err != nil {
+		return _err.New(_fmt.Sprintf("%s 檔案寫入失敗，Message：%s", path, err))
+	}
+
+	if stat, err := _os.Stat(partial); err != nil || uint64(stat.Size()) != meta.ContentLength {
+		return _err.New(_fmt.Sprintf("%s 檔案大小與 S3 不符，可再次呼叫續傳", path))
+	}
+
+	if response.StatusCode == 206 && meta.IsMd5 {
+		if md5, err := getFileMD5Hex(partial); err != nil || md5 != meta.Md5 {
+			_os.Remove(partial)
+			_os.Remove(path + RESUME_EXT)
+			return _err.New(_fmt.Sprintf("%s 續傳後的 MD5 與 S3 不符，請重新下載", path))
+		}
+	}
+
+	if err := place(partial, path, mode, keepTime, meta.Time); err != nil {
+		return err
+	}
+
+	_os.Remove(path + RESUME_EXT)
+	return nil
+}
@@ -0,0 +1,179 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_md5 "crypto/md5"
+	_fmt "fmt"
+	_http "net/http"
+	_os "os"
+	_fs "path/filepath"
+	_model "s3/model"
+	_strconv "strconv"
+	_str "strings"
+	_sync "sync"
+	_testing "testing"
+)
+
+func TestRangeHeader(t *_testing.T) {
+	tests := []struct {
+		offset int64
+		length int64
+		want   string
+		fail   bool
+	}{
+		{0, 10, "bytes=0-9", false},
+		{100, 1, "bytes=100-100", false},
+		{100, -1, "bytes=100-", false},
+		{-500, -1, "bytes=-500", false},
+		{-500, 10, "", true},
+		{0, 0, "", true},
+	}
+
+	for _, test := range tests {
+		got, err := rangeHeader(test.offset, test.length)
+		if test.fail {
+			if err == nil {
+				t.Errorf("rangeHeader(%d, %d)：預期錯誤，實際 %s", test.offset, test.length, got)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("rangeHeader(%d, %d)：預期 %s，實際 %s，錯誤 %v", test.offset, test.length, test.want, got, err)
+		}
+	}
+}
+func TestParseContentRange(t *_testing.T) {
+	tests := []struct {
+		val  string
+		want *_model.ContentRange
+	}{
+		{"bytes 0-9/100", &_model.ContentRange{Start: 0, End: 9, Total: 100}},
+		{" bytes 99-99/100 ", &_model.ContentRange{Start: 99, End: 99, Total: 100}},
+		{"bytes 0-99/100", &_model.ContentRange{Start: 0, End: 99, Total: 100}},
+		{"bytes 0-100/100", nil},
+		{"bytes 10-9/100", nil},
+		{"bytes */100", nil},
+		{"bytes 0-9/*", nil},
+		{"", nil},
+	}
+
+	for _, test := range tests {
+		got, err := parseContentRange(test.val)
+		if test.want == nil {
+			if err == nil {
+				t.Errorf("parseContentRange(%q)：預期錯誤，實際 %+v", test.val, got)
+			}
+			continue
+		}
+		if err != nil || *got != *test.want {
+			t.Errorf("parseContentRange(%q)：預期 %+v，實際 %+v，錯誤 %v", test.val, test.want, got, err)
+		}
+	}
+}
+
+// 提供單一檔案的 HEAD、GET 與 Range，fail 回傳 true 的範圍會以 412 回應
+func serveObject(data []byte, fail func(offset int) bool, ranges *[]string) _http.HandlerFunc {
+	mutex := new(_sync.Mutex)
+	etag := _fmt.Sprintf("\"%x\"", _md5.Sum(data))
+
+	return func(w _http.ResponseWriter, r *_http.Request) {
+		w.Header().Set("ETag", etag)
+		w.Header().Set("Last-Modified", "Mon, 02 Jan 2006 15:04:05 GMT")
+		w.Header().Set("Content-Type", "application/octet-stream")
+
+		header := r.Header.Get("Range")
+		if header == "" {
+			w.Header().Set("Content-Length", _strconv.Itoa(len(data)))
+			if r.Method == _http.MethodGet {
+				w.Write(data)
+			}
+			return
+		}
+
+		mutex.Lock()
+		*ranges = append(*ranges, header)
+		mutex.Unlock()
+
+		var start, end int
+		_fmt.Sscanf(header, "bytes=%d-%d", &start, &end)
+		if r.Header.Get("If-Match") != etag || fail(start) {
+			w.WriteHeader(_http.StatusPreconditionFailed)
+			_fmt.Fprint(w, "<Error><Code>PreconditionFailed</Code></Error>")
+			return
+		}
+
+		w.Header().Set("Content-Range", _fmt.Sprintf("bytes %d-%d/%d", start, end, len(data)))
+		w.Header().Set("Content-Length", _strconv.Itoa(end-start+1))
+		w.WriteHeader(_http.StatusPartialContent)
+		w.Write(data[start : end+1])
+	}
+}
+
+func TestDownload(t *_testing.T) {
+	data := []byte(_str.Repeat("0123456789abcdef", 12*1024*1024/16))
+	ranges := []string{}
+	bucket := stub(t, "bk/big.bin", serveObject(data, func(int) bool { return false }, &ranges))
+
+	path := _fs.Join(t.TempDir(), "big.bin")
+	if err := bucket.Download(path, _Parts{size: 1, goroutines: 2}, _Mode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(ranges) != 3 {
+		t.Errorf("分段大小應至少為 %d，實際送出 %d 個範圍", PART_MIN_SIZE, len(ranges))
+	}
+
+	got, err := _os.ReadFile(path)
+	if err != nil || string(got) != string(data) {
+		t.Fatalf("下載內容不符，錯誤 %v", err)
+	}
+
+	if stat, _ := _os.Stat(path); stat.Mode().Perm() != 0600 {
+		t.Errorf("權限應為 0600，實際 %s", stat.Mode().Perm())
+	}
+
+	if matches, _ := _fs.Glob(_fs.Join(_fs.Dir(path), "*"+TEMP_EXT)); len(matches) != 0 {
+		t.Errorf("暫存檔沒有被清除 %v", matches)
+	}
+}
+func TestDownloadKeepsFileOnFailure(t *_testing.T) {
+	data := []byte(_str.Repeat("x", 12*1024*1024))
+	ranges := []string{}
+	bucket := stub(t, "bk/big.bin", serveObject(data, func(offset int) bool { return offset > 0 }, &ranges))
+
+	path := _fs.Join(t.TempDir(), "big.bin")
+	if err := _os.WriteFile(path, []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := bucket.Download(path, _Parts{size: PART_MIN_SIZE}); err == nil {
+		t.Fatal("範圍回應 412 時應該失敗")
+	}
+
+	if got, _ := _os.ReadFile(path); string(got) != "old" {
+		t.Errorf("原本的檔案被破壞，內容長度 %d", len(got))
+	}
+
+	if matches, _ := _fs.Glob(_fs.Join(_fs.Dir(path), "*"+TEMP_EXT)); len(matches) != 0 {
+		t.Errorf("暫存檔沒有被清除 %v", matches)
+	}
+}
+func TestDownloadSmallUsesSaveArgs(t *_testing.T) {
+	ranges := []string{}
+	bucket := stub(t, "bk/small.txt", serveObject([]byte("hello"), func(int) bool { return false }, &ranges))
+
+	path := _fs.Join(t.TempDir(), "small.txt")
+	if err := bucket.Download(path, _Mode(0600)); err != nil {
+		t.Fatal(err)
+	}
+
+	if stat, err := _os.Stat(path); err != nil || stat.Mode().Perm() != 0600 {
+		t.Errorf("小檔案應沿用 Save 的參數，錯誤 %v", err)
+	}
+}
@@ -0,0 +1,126 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_xml "encoding/xml"
+	_err "errors"
+	_fmt "fmt"
+	_url "net/url"
+	_enum "s3/enum"
+	_model "s3/model"
+	_req "s3/request"
+	_str "strings"
+	_time "time"
+)
+
+func decodeKey(encoding string, key string) (string, error) {
+	if encoding != "url" {
+		return key, nil
+	}
+	return _url.QueryUnescape(key)
+}
+
+func (bucket *Bucket) List(wheres ..._Where) (*_model.Listing, error) {
+	listing := &_model.Listing{Files: []*_model.File{}, Prefixes: []string{}}
+
+	if bucket == nil {
+		return listing, _err.New("錯誤的 Bucket")
+	}
+
+	req := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Method(_enum.METHOD_GET).Parameter("list-type", "2").Parameter("encoding-type", "url")
+
+	if len(wheres) > 0 && wheres[0] != nil {
+		where := wheres[0]
+
+		if val := where.PrefixStr(); val != nil {
+			req.Parameter("prefix", *val)
+		}
+		if val := where.ExcludeStr(); val != nil {
+			req.Parameter("delimiter", *val)
+		}
+		if val := where.LimitNum(); val != nil {
+			req.Parameter("max-keys", _fmt.Sprintf("%d", *val))
+		}
+		if val := where.TokenStr(); val != nil {
+			req.Parameter("continuation-token", *val)
+		}
+		if val := where.StartAfterStr(); val != nil {
+			req.Parameter("start-after", *val)
+		}
+		if where.IsFetchOwner() {
+			req.Parameter("fetch-owner", "true")
+		}
+	}
+
+	response := req.Response()
+	if err := response.IsSuccess(); err != nil {
+		return listing, err
+	}
+
+	var result *struct {
+		IsTruncated bool   `xml:"IsTruncated"`
+		NextToken   string `xml:"NextContinuationToken"`
+		Encoding    string `xml:"EncodingType"`
+
+		Contents []struct {
+			Key   string `xml:"Key"`
+			Time  string `xml:"LastModified"`
+			ETag  string `xml:"ETag"`
+			Size  uint64 `xml:"Size"`
+			Owner struct {
+				Id   string `xml:"ID"`
+				Name string `xml:"DisplayName"`
+			} `xml:"Owner"`
+		} `xml:"Contents"`
+
+		CommonPrefixes []struct {
+			Prefix string `xml:"Prefix"`
+		} `xml:"CommonPrefixes"`
+	} = nil
+
+	if err := _xml.Unmarshal(response.BodyBytes, &result); err != nil {
+		return listing, _err.New(_fmt.Sprintf("編譯 XML 失敗，Message：%s", err))
+	}
+
+	for _, content := range result.Contents {
+		key, err := decodeKey(result.Encoding, content.Key)
+		if err != nil {
+			return listing, _err.New(_fmt.Sprintf("解碼檔案名稱 %s 失敗，Message：%s", content.Key, err))
+		}
+
+		time, err := _time.Parse("2006-01-02T15:04:05.999Z", content.Time)
+		if err != nil {
+			return listing, _err.New(_fmt.Sprintf("轉換時間格式失敗，Message：%s", err))
+		}
+
+		listing.Files = append(listing.Files, &_model.File{
+			Key:       key,
+			Time:      uint64(time.Unix()),
+			Md5:       _str.Trim(content.ETag, "\""),
+			Size:      content.Size,
+			OwnerId:   content.Owner.Id,
+			OwnerName: content.Owner.Name,
+		})
+	}
+
+	for _, common := range result.CommonPrefixes {
+		prefix, err := decodeKey(result.Encoding, common.Prefix)
+		if err != nil {
+			return listing, _err.New(_fmt.Sprintf("解碼目錄名稱 %s 失敗，Message：%s", common.Prefix, err))
+		}
+		listing.Prefixes = append(listing.Prefixes, prefix)
+	}
+
+	listing.IsTruncated = result.IsTruncated
+	if result.IsTruncated {
+		listing.NextToken = result.NextToken
+	}
+
+	return listing, nil
+}
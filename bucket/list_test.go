@@ -0,0 +1,110 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_fmt "fmt"
+	_http "net/http"
+	_url "net/url"
+	_testing "testing"
+)
+
+type _List struct {
+	prefix     string
+	exclude    string
+	token      string
+	startAfter string
+	fetchOwner bool
+}
+
+func (where _List) GetWhereInterface()     {}
+func (where _List) PrefixStr() *string     { return optional(where.prefix) }
+func (where _List) NextKeyStr() *string    { return nil }
+func (where _List) ExcludeStr() *string    { return optional(where.exclude) }
+func (where _List) LimitNum() *uint64      { return nil }
+func (where _List) TokenStr() *string      { return optional(where.token) }
+func (where _List) StartAfterStr() *string { return optional(where.startAfter) }
+func (where _List) IsFetchOwner() bool     { return where.fetchOwner }
+
+func optional(str string) *string {
+	if str == "" {
+		return nil
+	}
+	return &str
+}
+
+func TestList(t *_testing.T) {
+	var query _url.Values
+
+	bucket := stub(t, "bk", func(w _http.ResponseWriter, r *_http.Request) {
+		query = r.URL.Query()
+		_fmt.Fprint(w, "<ListBucketResult><IsTruncated>true</IsTruncated><NextContinuationToken>next+token</NextContinuationToken><EncodingType>url</EncodingType>")
+		_fmt.Fprint(w, "<Contents><Key>a%20b%2Bc.txt</Key><LastModified>2020-01-01T00:00:00.000Z</LastModified><ETag>\"etag\"</ETag><Size>3</Size><Owner><ID>id</ID></Owner></Contents>")
+		_fmt.Fprint(w, "</ListBucketResult>")
+	})
+
+	listing, err := bucket.List(_List{prefix: "a ", token: "prev", startAfter: "a", fetchOwner: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, want := range map[string]string{"list-type": "2", "encoding-type": "url", "prefix": "a ", "continuation-token": "prev", "start-after": "a", "fetch-owner": "true"} {
+		if got := query.Get(key); got != want {
+			t.Errorf("參數 %s 預期 %q，實際 %q", key, want, got)
+		}
+	}
+
+	if len(listing.Files) != 1 || listing.Files[0].Key != "a b+c.txt" || listing.Files[0].OwnerId != "id" {
+		t.Errorf("檔案名稱應該經過 url 解碼，實際 %+v", listing.Files)
+	}
+
+	if !listing.IsTruncated || listing.NextToken != "next+token" {
+		t.Errorf("分頁錯誤 %v %q", listing.IsTruncated, listing.NextToken)
+	}
+}
+
+func TestFilesRejectsListWhere(t *_testing.T) {
+	bucket := stub(t, "bk", func(w _http.ResponseWriter, r *_http.Request) {
+		t.Errorf("不應該送出請求 %s", r.URL)
+	})
+
+	for _, where := range []_List{{token: "t"}, {startAfter: "a"}, {fetchOwner: true}} {
+		if _, err := bucket.Files(where); err == nil {
+			t.Errorf("Files 帶入 %+v 應該失敗", where)
+		}
+	}
+}
+
+func TestListPrefixes(t *_testing.T) {
+	var query _url.Values
+
+	bucket := stub(t, "bk", func(w _http.ResponseWriter, r *_http.Request) {
+		query = r.URL.Query()
+		_fmt.Fprint(w, "<ListBucketResult><IsTruncated>false</IsTruncated><EncodingType>url</EncodingType>")
+		_fmt.Fprint(w, "<Contents><Key>photos/a.jpg</Key><LastModified>2020-01-01T00:00:00.000Z</LastModified><ETag>\"etag\"</ETag><Size>1</Size></Contents>")
+		_fmt.Fprintf(w, "<CommonPrefixes><Prefix>photos/2020/</Prefix></CommonPrefixes><CommonPrefixes><Prefix>photos/%s/</Prefix></CommonPrefixes>", _url.PathEscape("旅遊"))
+		_fmt.Fprint(w, "</ListBucketResult>")
+	})
+
+	listing, err := bucket.List(_List{prefix: "photos/", exclude: "/"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if query.Get("delimiter") != "/" {
+		t.Errorf("delimiter 錯誤 %q", query.Get("delimiter"))
+	}
+
+	if _fmt.Sprint(listing.Prefixes) != "[photos/2020/ photos/旅遊/]" {
+		t.Errorf("目錄錯誤 %v", listing.Prefixes)
+	}
+
+	if len(listing.Files) != 1 || listing.Files[0].Key != "photos/a.jpg" || listing.IsTruncated || listing.NextToken != "" {
+		t.Errorf("結果錯誤 %+v", listing)
+	}
+}
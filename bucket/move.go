@@ -0,0 +1,145 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_context "context"
+	_err "errors"
+	_fmt "fmt"
+	_model "s3/model"
+	_str "strings"
+	_sync "sync"
+)
+
+type _Prefix string
+
+func (prefix _Prefix) GetWhereInterface() {}
+func (prefix _Prefix) PrefixStr() *string {
+	str := string(prefix)
+	return &str
+}
+func (prefix _Prefix) NextKeyStr() *string    { return nil }
+func (prefix _Prefix) ExcludeStr() *string    { return nil }
+func (prefix _Prefix) LimitNum() *uint64      { return nil }
+func (prefix _Prefix) TokenStr() *string      { return nil }
+func (prefix _Prefix) StartAfterStr() *string { return nil }
+func (prefix _Prefix) IsFetchOwner() bool     { return false }
+
+func (bucket *Bucket) MoveTo(dest string, args ...interface{}) error {
+	if bucket == nil {
+		return _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	target := bucket.s3.Bucket(dest)
+	if target == nil || target.uri == "" {
+		return _err.New(_fmt.Sprintf("目的地的沒有指定 S3 路徑"))
+	}
+
+	return bucket.moveTo(target.WithContext(bucket.Context()), args...)
+}
+func (bucket *Bucket) moveTo(target *Bucket, args ...interface{}) error {
+	if target.name == bucket.name && target.uri == bucket.uri {
+		return _err.New("來源與目的地相同")
+	}
+
+	meta, err := bucket.Meta()
+	if err != nil {
+		return err
+	}
+
+	if err := copy(bucket.s3, bucket, target, args...); err != nil {
+		return err
+	}
+
+	copied, err := target.Meta()
+	if err != nil {
+		return _fmt.Errorf("無法確認複製結果，保留來源檔案，Message：%w", err)
+	}
+
+	if copied.ContentLength != meta.ContentLength {
+		return _err.New(_fmt.Sprintf("複製後的檔案大小 %d 與來源 %d 不符，保留來源檔案", copied.ContentLength, meta.ContentLength))
+	}
+
+	if meta.IsMd5 && copied.IsMd5 && copied.Md5 != meta.Md5 {
+		return _err.New(_fmt.Sprintf("複製後的檔案 ETag %s 與來源 %s 不符，保留來源檔案", copied.Md5, meta.Md5))
+	}
+
+	return bucket.Del()
+}
+func (bucket *Bucket) MovePrefix(dest string, args ...interface{}) (*_model.Report, error) {
+	report := &_model.Report{Succeeded: []string{}, Failures: []*_model.Failure{}}
+
+	if bucket == nil {
+		return report, _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return report, _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	target := bucket.s3.Bucket(dest)
+	if target == nil {
+		return report, _err.New("目的地的 Bucket 錯誤")
+	}
+
+	if target.name == bucket.name && target.uri == bucket.uri {
+		return report, _err.New("來源與目的地相同")
+	}
+
+	from := bucket.uri + "/"
+	to := ""
+	if target.uri != "" {
+		to = target.uri + "/"
+	}
+
+	files, err := bucket.Files(_Prefix(from))
+	if err != nil {
+		return report, err
+	}
+
+	gor, rate := parseBatch(args)
+
+	pool := newPool(bucket.Context(), gor, rate, false)
+	mutex := new(_sync.Mutex)
+
+	report.Total = uint64(len(files))
+
+	for _, file := range files {
+		key := file.Key
+		if !pool.Go(func(ctx _context.Context) error {
+			source := &Bucket{s3: bucket.s3, name: bucket.name, uri: key, ctx: ctx}
+
+			var err error
+			if uri := to + _str.TrimPrefix(key, from); uri != "" {
+				err = source.moveTo(&Bucket{s3: bucket.s3, name: target.name, uri: uri, ctx: ctx}, args...)
+			} else {
+				err = source.Del()
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+
+			if err != nil {
+				report.Failures = append(report.Failures, &_model.Failure{Key: key, Error: err})
+				return _fmt.Errorf("搬移檔案 %s 時發生錯誤，Message：%w", key, err)
+			}
+
+			report.Success++
+			report.Succeeded = append(report.Succeeded, key)
+			return nil
+		}) {
+			break
+		}
+	}
+
+	return report, _err.Join(pool.Wait(), bucket.Context().Err())
+}
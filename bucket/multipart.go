@@ -0,0 +1,398 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_xml "encoding/xml"
+	_err "errors"
+	_fmt "fmt"
+	_io "io"
+	_fs "path/filepath"
+	_enum "s3/enum"
+	_model "s3/model"
+	_req "s3/request"
+	_resp "s3/request/response"
+	_str "strings"
+	_time "time"
+)
+
+const (
+	PART_MIN_SIZE = 5 * 1024 * 1024
+	PART_MAX_SIZE = 5 * 1024 * 1024 * 1024
+	PART_MAX_NUM  = 10000
+)
+
+func errorOfBody(statusCode uint16, body []byte) error {
+	var result *struct {
+		XMLName   _xml.Name
+		Code      string `xml:"Code"`
+		Message   string `xml:"Message"`
+		Resource  string `xml:"Resource"`
+		RequestId string `xml:"RequestId"`
+		HostId    string `xml:"HostId"`
+	} = nil
+
+	if err := _xml.Unmarshal(body, &result); err != nil || result == nil || result.XMLName.Local != "Error" {
+		return nil
+	}
+
+	return &_resp.Error{StatusCode: statusCode, Code: result.Code, Message: result.Message, Resource: result.Resource, RequestId: result.RequestId, HostId: result.HostId}
+}
+
+func isNotFound(err error) bool {
+	return _err.Is(err, _resp.ErrNotFound) || _err.Is(err, _resp.ErrNoSuchUpload)
+}
+
+func (bucket *Bucket) CreateMultipart(args ...interface{}) (*_model.Upload, error) {
+	if bucket == nil {
+		return nil, _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return nil, _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	acl, cache, cType := parseArgs(args)
+	if cType == "" {
+		if mime, ok := _exts[_fs.Ext(bucket.uri)]; ok {
+			cType = mime
+		} else {
+			cType = "application/octet-stream"
+		}
+	}
+
+	_, storage, metas, tagging := parseObjectArgs(args)
+
+	request := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_POST).Parameter("uploads", "").SetHeader("Content-Type", cType).SetAmzHeader("x-amz-acl", acl.Str()).SetHeader("Cache-Control", cache).SetAmzHeader("x-amz-tagging", tagging)
+	for key, val := range metas {
+		request = request.SetAmzHeader(key, val)
+	}
+	for _, arg := range args {
+		if headers, ok := arg.(_Headers); ok {
+			for key, val := range headers {
+				request = request.SetHeader(key, val)
+			}
+		}
+	}
+	if storage != _enum.STORAGE_STANDARD {
+		request = request.SetAmzHeader("x-amz-storage-class", storage.Str())
+	}
+
+	response := request.Response()
+	if err := response.IsSuccess(); err != nil {
+		return nil, err
+	}
+
+	var result *struct {
+		Key      string `xml:"Key"`
+		UploadId string `xml:"UploadId"`
+	} = nil
+
+	if err := _xml.Unmarshal(response.BodyBytes, &result); err != nil {
+		return nil, _err.New(_fmt.Sprintf("編譯 XML 失敗，Message：%s", err))
+	}
+
+	if result.UploadId == "" {
+		return nil, _err.New("錯誤，回應結果缺少 UploadId")
+	}
+
+	if result.Key == "" {
+		result.Key = bucket.uri
+	}
+
+	return &_model.Upload{Key: result.Key, UploadId: result.UploadId, Time: uint64(_time.Now().Unix())}, nil
+}
+func (bucket *Bucket) UploadPart(uploadId string, number uint, reader _io.Reader, size int64) (*_model.Part, error) {
+	if bucket == nil {
+		return nil, _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return nil, _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	if uploadId == "" {
+		return nil, _err.New("沒有指定 UploadId")
+	}
+
+	if number < 1 || number > PART_MAX_NUM {
+		return nil, _err.New(_fmt.Sprintf("分段編號 %d 錯誤，需介於 1 ~ %d", number, PART_MAX_NUM))
+	}
+
+	if reader == nil || size < 0 || size > PART_MAX_SIZE {
+		return nil, _err.New(_fmt.Sprintf("分段 %d 的資料錯誤", number))
+	}
+
+	cMd5 := ""
+	if seeker, ok := reader.(_io.ReadSeeker); ok {
+		md5, err := getReaderMD5(seeker, uint64(size))
+		if err != nil {
+			return nil, _err.New(_fmt.Sprintf("無法取得分段 %d 的 MD5 結果，Message：%s", number, err))
+		}
+		cMd5 = md5
+	}
+
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_PUT).Parameter("partNumber", _fmt.Sprintf("%d", number)).Parameter("uploadId", uploadId).SetHeader("Content-MD5", cMd5).SetReader(reader, uint64(size)).Response()
+	if err := response.IsSuccess(); err != nil {
+		return nil, err
+	}
+
+	etag, ok := response.Headers["Etag"]
+	if !ok {
+		return nil, _err.New("資訊有缺，缺少 Etag")
+	}
+
+	return &_model.Part{Number: number, ETag: _str.Trim(etag, "\""), Size: uint64(size), Time: uint64(_time.Now().Unix())}, nil
+}
+func (bucket *Bucket) UploadPartCopy(uploadId string, number uint, src string, offset int64, length int64, etags ...string) (*_model.Part, error) {
+	source, err := New(src, nil)
+	if err != nil || source.uri == "" {
+		return nil, _err.New(_fmt.Sprintf("被複製的路徑 %s 錯誤", src))
+	}
+
+	etag := ""
+	if len(etags) > 0 {
+		etag = etags[0]
+	}
+
+	return bucket.uploadPartCopy(uploadId, number, source, offset, length, etag)
+}
+func (bucket *Bucket) uploadPartCopy(uploadId string, number uint, source *Bucket, offset int64, length int64, etag string) (*_model.Part, error) {
+	if bucket == nil {
+		return nil, _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return nil, _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	if uploadId == "" {
+		return nil, _err.New("沒有指定 UploadId")
+	}
+
+	if number < 1 || number > PART_MAX_NUM {
+		return nil, _err.New(_fmt.Sprintf("分段編號 %d 錯誤，需介於 1 ~ %d", number, PART_MAX_NUM))
+	}
+
+	if offset < 0 || length <= 0 || length > PART_MAX_SIZE {
+		return nil, _err.New(_fmt.Sprintf("分段 %d 的範圍錯誤", number))
+	}
+
+	if etag != "" {
+		etag = _fmt.Sprintf("\"%s\"", _str.Trim(etag, "\""))
+	}
+
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_PUT).Parameter("partNumber", _fmt.Sprintf("%d", number)).Parameter("uploadId", uploadId).SetAmzHeader("x-amz-copy-source", copySource(source)).SetAmzHeader("x-amz-copy-source-range", _fmt.Sprintf("bytes=%d-%d", offset, offset+length-1)).SetAmzHeader("x-amz-copy-source-if-match", etag).Response()
+	if err := response.IsSuccess(); err != nil {
+		return nil, err
+	}
+
+	if err := errorOfBody(response.StatusCode, response.BodyBytes); err != nil {
+		return nil, err
+	}
+
+	var result *struct {
+		ETag string `xml:"ETag"`
+	} = nil
+
+	if err := _xml.Unmarshal(response.BodyBytes, &result); err != nil {
+		return nil, _err.New(_fmt.Sprintf("編譯 XML 失敗，Message：%s", err))
+	}
+
+	if result.ETag == "" {
+		return nil, _err.New("資訊有缺，缺少 ETag")
+	}
+
+	return &_model.Part{Number: number, ETag: _str.Trim(result.ETag, "\""), Size: uint64(length), Time: uint64(_time.Now().Unix())}, nil
+}
+func (bucket *Bucket) CompleteMultipart(uploadId string, parts []*_model.Part) error {
+	if bucket == nil {
+		return _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	if uploadId == "" {
+		return _err.New("沒有指定 UploadId")
+	}
+
+	if len(parts) == 0 {
+		return _err.New("沒有任何分段")
+	}
+
+	type _Part struct {
+		Number uint   `xml:"PartNumber"`
+		ETag   string `xml:"ETag"`
+	}
+
+	items := []_Part{}
+	for i, part := range parts {
+		if part == nil {
+			return _err.New(_fmt.Sprintf("第 %d 個分段錯誤", i+1))
+		}
+		if i > 0 && parts[i-1].Number >= part.Number {
+			return _err.New("分段編號需由小到大排序且不可重複")
+		}
+		items = append(items, _Part{Number: part.Number, ETag: _fmt.Sprintf("\"%s\"", part.ETag)})
+	}
+
+	encoder, err := _xml.MarshalIndent(struct {
+		XMLName _xml.Name `xml:"CompleteMultipartUpload"`
+		Parts   []_Part   `xml:"Part"`
+	}{Parts: items}, "", "  ")
+
+	if err != nil {
+		return _err.New(_fmt.Sprintf("產生 XML 失敗，Message：%s", err))
+	}
+
+	response := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_POST).Parameter("uploadId", uploadId).SetXML(_fmt.Sprintf("%s%s", _xml.Header, string(encoder))).Response()
+	if err := response.IsSuccess(); err != nil {
+		return err
+	}
+
+	if err := errorOfBody(response.StatusCode, response.BodyBytes); err != nil {
+		return err
+	}
+
+	return nil
+}
+func (bucket *Bucket) AbortMultipart(uploadId string) error {
+	if bucket == nil {
+		return _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	if uploadId == "" {
+		return _err.New("沒有指定 UploadId")
+	}
+
+	return _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_DELETE).Parameter("uploadId", uploadId).Response().IsSuccess([]uint16{200, 204})
+}
+func (bucket *Bucket) Parts(uploadId string) ([]*_model.Part, error) {
+	parts := []*_model.Part{}
+
+	if bucket == nil {
+		return parts, _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return parts, _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	if uploadId == "" {
+		return parts, _err.New("沒有指定 UploadId")
+	}
+
+	marker := ""
+	for {
+		req := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Uri(bucket.uri).Method(_enum.METHOD_GET).Parameter("uploadId", uploadId)
+		if marker != "" {
+			req.Parameter("part-number-marker", marker)
+		}
+
+		response := req.Response()
+		if err := response.IsSuccess(); err != nil {
+			return parts, err
+		}
+
+		var result *struct {
+			IsTruncated bool   `xml:"IsTruncated"`
+			NextMarker  string `xml:"NextPartNumberMarker"`
+
+			Parts []struct {
+				Number uint   `xml:"PartNumber"`
+				Time   string `xml:"LastModified"`
+				ETag   string `xml:"ETag"`
+				Size   uint64 `xml:"Size"`
+			} `xml:"Part"`
+		} = nil
+
+		if err := _xml.Unmarshal(response.BodyBytes, &result); err != nil {
+			return parts, _err.New(_fmt.Sprintf("編譯 XML 失敗，Message：%s", err))
+		}
+
+		for _, part := range result.Parts {
+			time, err := _time.Parse("2006-01-02T15:04:05.999Z", part.Time)
+			if err != nil {
+				return parts, _err.New(_fmt.Sprintf("轉換時間格式失敗，Message：%s", err))
+			}
+
+			parts = append(parts, &_model.Part{Number: part.Number, ETag: _str.Trim(part.ETag, "\""), Size: part.Size, Time: uint64(time.Unix())})
+		}
+
+		if !result.IsTruncated || result.NextMarker == "" || result.NextMarker == marker {
+			break
+		}
+		marker = result.NextMarker
+	}
+
+	return parts, nil
+}
+func (bucket *Bucket) Uploads() ([]*_model.Upload, error) {
+	uploads := []*_model.Upload{}
+
+	if bucket == nil {
+		return uploads, _err.New("錯誤的 Bucket")
+	}
+
+	keyMarker, idMarker := "", ""
+	for {
+		req := _req.New(bucket.s3).Context(bucket.ctx).Bucket(bucket.name).Method(_enum.METHOD_GET).Parameter("uploads", "")
+		if bucket.uri != "" {
+			req.Parameter("prefix", bucket.uri)
+		}
+		if keyMarker != "" {
+			req.Parameter("key-marker", keyMarker)
+		}
+		if idMarker != "" {
+			req.Parameter("upload-id-marker", idMarker)
+		}
+
+		response := req.Response()
+		if err := response.IsSuccess(); err != nil {
+			return uploads, err
+		}
+
+		var result *struct {
+			IsTruncated   bool   `xml:"IsTruncated"`
+			NextKeyMarker string `xml:"NextKeyMarker"`
+			NextIdMarker  string `xml:"NextUploadIdMarker"`
+
+			Uploads []struct {
+				Key      string `xml:"Key"`
+				UploadId string `xml:"UploadId"`
+				Time     string `xml:"Initiated"`
+			} `xml:"Upload"`
+		} = nil
+
+		if err := _xml.Unmarshal(response.BodyBytes, &result); err != nil {
+			return uploads, _err.New(_fmt.Sprintf("編譯 XML 失敗，Message：%s", err))
+		}
+
+		for _, upload := range result.Uploads {
+			time, err := _time.Parse("2006-01-02T15:04:05.999Z", upload.Time)
+			if err != nil {
+				return uploads, _err.New(_fmt.Sprintf("轉換時間格式失敗，Message：%s", err))
+			}
+
+			uploads = append(uploads, &_model.Upload{Key: upload.Key, UploadId: upload.UploadId, Time: uint64(time.Unix())})
+		}
+
+		if !result.IsTruncated || result.NextKeyMarker == "" && result.NextIdMarker == "" {
+			break
+		}
+		keyMarker, idMarker = result.NextKeyMarker, result.NextIdMarker
+	}
+
+	return uploads, nil
+}
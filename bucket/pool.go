@@ -0,0 +1,132 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_context "context"
+	_err "errors"
+	_sync "sync"
+	_time "time"
+)
+
+type _Pool struct {
+	ctx    _context.Context
+	cancel _context.CancelFunc
+	jobs   chan func(_context.Context) error
+	wg     *_sync.WaitGroup
+	mutex  *_sync.Mutex
+	errs   []error
+	tick   *_time.Ticker
+	fast   bool
+}
+
+func parseBatch(args []interface{}) (int, uint) {
+	gor, rate := 4, uint(0)
+
+	for _, arg := range args {
+		val, ok := arg.(_Batch)
+		if !ok || val == nil {
+			continue
+		}
+		if num := val.GoroutinesNum(); num != nil {
+			gor = int(*num)
+		}
+		if num := val.RateNum(); num != nil {
+			rate = *num
+		}
+	}
+
+	return gor, rate
+}
+func newPool(ctx _context.Context, size int, rate uint, fast bool) *_Pool {
+	if ctx == nil {
+		ctx = _context.Background()
+	}
+	if size < 1 {
+		size = 1
+	}
+
+	ctx, cancel := _context.WithCancel(ctx)
+	pool := &_Pool{ctx: ctx, cancel: cancel, jobs: make(chan func(_context.Context) error), wg: new(_sync.WaitGroup), mutex: new(_sync.Mutex), errs: []error{}, fast: fast}
+
+	if rate > 0 {
+		interval := _time.Second / _time.Duration(rate)
+		if interval <= 0 {
+			interval = 1
+		}
+		pool.tick = _time.NewTicker(interval)
+	}
+
+	for i := 0; i < size; i++ {
+		pool.wg.Add(1)
+		go pool.work()
+	}
+
+	return pool
+}
+
+func (pool *_Pool) work() {
+	defer pool.wg.Done()
+
+	for job := range pool.jobs {
+		if pool.ctx.Err() != nil {
+			continue
+		}
+
+		if pool.tick != nil {
+			select {
+			case <-pool.tick.C:
+			case <-pool.ctx.Done():
+				continue
+			}
+		}
+
+		if err := job(pool.ctx); err != nil {
+			pool.fail(err)
+		}
+	}
+}
+func (pool *_Pool) fail(err error) {
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	if pool.fast {
+		if len(pool.errs) == 0 {
+			pool.errs = append(pool.errs, err)
+			pool.cancel()
+		}
+		return
+	}
+
+	pool.errs = append(pool.errs, err)
+}
+func (pool *_Pool) Context() _context.Context {
+	return pool.ctx
+}
+func (pool *_Pool) Go(job func(_context.Context) error) bool {
+	select {
+	case pool.jobs <- job:
+		return true
+	case <-pool.ctx.Done():
+		return false
+	}
+}
+func (pool *_Pool) Wait() error {
+	close(pool.jobs)
+	pool.wg.Wait()
+	pool.cancel()
+
+	if pool.tick != nil {
+		pool.tick.Stop()
+	}
+
+	pool.mutex.Lock()
+	defer pool.mutex.Unlock()
+
+	return _err.Join(pool.errs...)
+}
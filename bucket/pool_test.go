@@ -0,0 +1,96 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_context "context"
+	_err "errors"
+	_atomic "sync/atomic"
+	_testing "testing"
+)
+
+func TestPoolFast(t *_testing.T) {
+	first, second := _err.New("first"), _err.New("second")
+	pool := newPool(_context.Background(), 1, 0, true)
+
+	ran := int32(0)
+	pool.Go(func(ctx _context.Context) error {
+		_atomic.AddInt32(&ran, 1)
+		return first
+	})
+
+	rejected := false
+	for i := 0; i < 1000 && !rejected; i++ {
+		rejected = !pool.Go(func(ctx _context.Context) error {
+			_atomic.AddInt32(&ran, 1)
+			return second
+		})
+	}
+
+	if !rejected {
+		t.Error("發生錯誤後應該拒絕新的工作")
+	}
+
+	err := pool.Wait()
+	if !_err.Is(err, first) || _err.Is(err, second) {
+		t.Errorf("應該只回傳第一個錯誤，實際 %v", err)
+	}
+
+	if ran != 1 {
+		t.Errorf("發生錯誤後不應該再執行工作，實際執行 %d 次", ran)
+	}
+}
+
+func TestPoolJoin(t *_testing.T) {
+	errs := []error{_err.New("a"), _err.New("b"), _err.New("c")}
+	pool := newPool(_context.Background(), 2, 0, false)
+
+	ran := int32(0)
+	for _, err := range errs {
+		err := err
+		if !pool.Go(func(ctx _context.Context) error {
+			_atomic.AddInt32(&ran, 1)
+			return err
+		}) {
+			t.Fatal("沒有 fast 時不應該拒絕工作")
+		}
+	}
+	pool.Go(func(ctx _context.Context) error {
+		_atomic.AddInt32(&ran, 1)
+		return nil
+	})
+
+	err := pool.Wait()
+	for _, want := range errs {
+		if !_err.Is(err, want) {
+			t.Errorf("錯誤應該包含 %v，實際 %v", want, err)
+		}
+	}
+
+	if ran != 4 {
+		t.Errorf("應該執行全部 4 個工作，實際 %d 個", ran)
+	}
+}
+
+func TestPoolCancel(t *_testing.T) {
+	ctx, cancel := _context.WithCancel(_context.Background())
+	pool := newPool(ctx, 2, 10, false)
+	cancel()
+
+	ran := int32(0)
+	for i := 0; i < 10; i++ {
+		pool.Go(func(ctx _context.Context) error {
+			_atomic.AddInt32(&ran, 1)
+			return nil
+		})
+	}
+
+	if err := pool.Wait(); err != nil || ran != 0 {
+		t.Errorf("取消後不應該執行工作，執行 %d 次，錯誤 %v", ran, err)
+	}
+}
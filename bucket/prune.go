@@ -0,0 +1,91 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_err "errors"
+	_fmt "fmt"
+	_regx "regexp"
+	_model "s3/model"
+	_time "time"
+)
+
+func (bucket *Bucket) Prune(prune _Prune, args ...interface{}) ([]*_model.File, *_model.Report, error) {
+	matches := []*_model.File{}
+	report := &_model.Report{Succeeded: []string{}, Failures: []*_model.Failure{}}
+
+	if bucket == nil {
+		return matches, report, _err.New("錯誤的 Bucket")
+	}
+
+	if prune == nil {
+		return matches, report, _err.New("沒有指定清除條件")
+	}
+
+	if !prune.IsDryRun() && prune.PrefixStr() == nil && prune.OlderThanNum() == nil && prune.MinSizeNum() == nil && prune.MaxSizeNum() == nil && prune.PatternStr() == nil {
+		return matches, report, _err.New("沒有指定任何清除條件，若要刪除全部檔案請使用 Clean")
+	}
+
+	prefix := ""
+	if bucket.uri != "" {
+		prefix = bucket.uri + "/"
+	}
+	if val := prune.PrefixStr(); val != nil {
+		prefix = prefix + *val
+	}
+
+	var pattern *_regx.Regexp = nil
+	if val := prune.PatternStr(); val != nil {
+		regx, err := _regx.Compile(*val)
+		if err != nil {
+			return matches, report, _err.New(_fmt.Sprintf("檔案名稱規則 %s 格式錯誤，Message：%s", *val, err))
+		}
+		pattern = regx
+	}
+
+	cutoff := uint64(0)
+	if val := prune.OlderThanNum(); val != nil {
+		cutoff = uint64(_time.Now().Add(-_time.Duration(*val) * 24 * _time.Hour).Unix())
+	}
+
+	files, err := bucket.Files(_Prefix(prefix))
+	if err != nil {
+		return matches, report, err
+	}
+
+	for _, file := range files {
+		if cutoff > 0 && file.Time >= cutoff {
+			continue
+		}
+		if val := prune.MinSizeNum(); val != nil && file.Size < *val {
+			continue
+		}
+		if val := prune.MaxSizeNum(); val != nil && file.Size > *val {
+			continue
+		}
+		if pattern != nil && !pattern.MatchString(file.Key) {
+			continue
+		}
+		matches = append(matches, file)
+	}
+
+	report.Total = uint64(len(matches))
+
+	if prune.IsDryRun() {
+		return matches, report, nil
+	}
+
+	keys := []string{}
+	for _, file := range matches {
+		keys = append(keys, file.Key)
+	}
+
+	gor, rate := parseBatch(args)
+	report, err = bucket.deleteKeys(keys, gor, rate, prune.ProgressFunc())
+	return matches, report, err
+}
@@ -0,0 +1,65 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_fmt "fmt"
+	_http "net/http"
+	_testing "testing"
+)
+
+type _PruneArgs struct {
+	pattern string
+	dryRun  bool
+}
+
+func (prune _PruneArgs) GetPruneInterface()  {}
+func (prune _PruneArgs) PrefixStr() *string  { return nil }
+func (prune _PruneArgs) OlderThanNum() *uint { return nil }
+func (prune _PruneArgs) MinSizeNum() *uint64 { return nil }
+func (prune _PruneArgs) MaxSizeNum() *uint64 { return nil }
+func (prune _PruneArgs) PatternStr() *string {
+	if prune.pattern == "" {
+		return nil
+	}
+	return &prune.pattern
+}
+func (prune _PruneArgs) IsDryRun() bool                                { return prune.dryRun }
+func (prune _PruneArgs) ProgressFunc() func(done uint64, total uint64) { return nil }
+
+func TestPrune(t *_testing.T) {
+	deleted := 0
+
+	bucket := stub(t, "bk/logs", func(w _http.ResponseWriter, r *_http.Request) {
+		if r.Method == _http.MethodPost {
+			deleted++
+			_fmt.Fprint(w, "<DeleteResult></DeleteResult>")
+			return
+		}
+		w.Header().Set("Content-Type", "application/xml")
+		_fmt.Fprint(w, "<ListBucketResult><IsTruncated>false</IsTruncated>")
+		for _, key := range []string{"logs/a.log", "logs/b.txt"} {
+			_fmt.Fprintf(w, "<Contents><Key>%s</Key><LastModified>2020-01-01T00:00:00.000Z</LastModified><ETag>\"etag\"</ETag><Size>1</Size></Contents>", key)
+		}
+		_fmt.Fprint(w, "</ListBucketResult>")
+	})
+
+	if _, _, err := bucket.Prune(_PruneArgs{}); err == nil || deleted != 0 {
+		t.Errorf("沒有條件時應該拒絕執行，錯誤 %v，刪除請求 %d 次", err, deleted)
+	}
+
+	files, _, err := bucket.Prune(_PruneArgs{dryRun: true})
+	if err != nil || len(files) != 2 || deleted != 0 {
+		t.Errorf("DryRun 不帶條件時應該列出全部檔案，檔案 %d 個，錯誤 %v，刪除請求 %d 次", len(files), err, deleted)
+	}
+
+	files, report, err := bucket.Prune(_PruneArgs{pattern: `\.log$`})
+	if err != nil || len(files) != 1 || files[0].Key != "logs/a.log" || report.Success != 1 || deleted != 1 {
+		t.Errorf("結果錯誤，檔案 %v，報告 %+v，錯誤 %v", files, report, err)
+	}
+}
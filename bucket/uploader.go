@@ -0,0 +1,395 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_bytes "bytes"
+	_context "context"
+	_err "errors"
+	_fmt "fmt"
+	_io "io"
+	_os "os"
+	_fs "path/filepath"
+	_enum "s3/enum"
+	_model "s3/model"
+	_sort "sort"
+	_sync "sync"
+)
+
+const (
+	PART_DEFAULT_SIZE = 8 * 1024 * 1024
+	PART_COPY_SIZE    = 128 * 1024 * 1024
+)
+
+type _MetaMap map[string]string
+
+func (meta _MetaMap) GetMetaInterface() {}
+func (meta _MetaMap) MetaMap() map[string]string {
+	return meta
+}
+
+type _TagsMap map[string]string
+
+func (tags _TagsMap) GetTagsInterface() {}
+func (tags _TagsMap) TagsMap() map[string]string {
+	return tags
+}
+
+type _Headers map[string]string
+
+type _Job struct {
+	Number uint
+	Reader _io.ReadSeeker
+	Size   int64
+	Source *Bucket
+	ETag   string
+	Offset int64
+}
+
+func parseMultipart(args []interface{}) (uint64, int, int) {
+	size, gor, retry := uint64(PART_DEFAULT_SIZE), 4, 3
+
+	for _, arg := range args {
+		val, ok := arg.(_Multipart)
+		if !ok || val == nil {
+			continue
+		}
+		if num := val.PartSizeNum(); num != nil {
+			size = *num
+		}
+		if num := val.GoroutinesNum(); num != nil {
+			gor = int(*num)
+		}
+		if num := val.RetryNum(); num != nil {
+			retry = int(*num)
+		}
+	}
+
+	return size, gor, retry
+}
+func parseCheckpoint(args []interface{}) string {
+	for _, arg := range args {
+		if val, ok := arg.(_Multipart); ok && val != nil && val.CheckpointStr() != nil {
+			return *val.CheckpointStr()
+		}
+	}
+	return ""
+}
+func parseRate(args []interface{}) uint {
+	for _, arg := range args {
+		if val, ok := arg.(_Multipart); ok && val != nil && val.RateNum() != nil {
+			return *val.RateNum()
+		}
+	}
+	return 0
+}
+func partSize(total int64, size uint64) (uint64, error) {
+	if size < PART_MIN_SIZE {
+		size = PART_MIN_SIZE
+	}
+
+	if total > 0 {
+		if min := uint64((total + PART_MAX_NUM - 1) / PART_MAX_NUM); size < min {
+			size = (min + 1024*1024 - 1) / (1024 * 1024) * (1024 * 1024)
+		}
+	}
+
+	if size > PART_MAX_SIZE {
+		return 0, _err.New(_fmt.Sprintf("檔案過大，分段大小 %d 超過上限 %d", size, PART_MAX_SIZE))
+	}
+
+	return size, nil
+}
+
+func (bucket *Bucket) uploadPart(uploadId string, job _Job, retry int) (*_model.Part, error) {
+	var err error
+	for i := 0; i < retry || i == 0; i++ {
+		var part *_model.Part
+		if job.Source != nil {
+			part, err = bucket.uploadPartCopy(uploadId, job.Number, job.Source, job.Offset, job.Size, job.ETag)
+		} else {
+			part, err = bucket.UploadPart(uploadId, job.Number, job.Reader, job.Size)
+		}
+		if err == nil {
+			return part, nil
+		}
+		if bucket.Context().Err() != nil {
+			break
+		}
+		if job.Reader == nil {
+			continue
+		}
+		if _, e := job.Reader.Seek(0, _io.SeekStart); e != nil {
+			break
+		}
+	}
+	return nil, _fmt.Errorf("上傳分段 %d 失敗，Message：%w", job.Number, err)
+}
+func (bucket *Bucket) multipart(point *_Point, produce func(ctx _context.Context, submit func(_Job) bool) error, args ...interface{}) error {
+	_, gor, retry := parseMultipart(args)
+	if gor < 1 {
+		gor = 1
+	}
+
+	var upload *_model.Upload
+	if point != nil && point.state.UploadId != "" {
+		upload = &_model.Upload{Key: point.state.Key, UploadId: point.state.UploadId, Time: point.state.Time}
+	} else {
+		created, err := bucket.CreateMultipart(args...)
+		if err != nil {
+			return err
+		}
+		upload = created
+
+		if point != nil {
+			point.state.UploadId = upload.UploadId
+			if err := point.save(nil); err != nil {
+				bucket.AbortMultipart(upload.UploadId)
+				return _err.New(_fmt.Sprintf("無法寫入檢查點 %s，Message：%s", point.state.File, err))
+			}
+		}
+	}
+
+	pool := newPool(bucket.Context(), gor, parseRate(args), true)
+	mutex := new(_sync.Mutex)
+	parts := []*_model.Part{}
+
+	err := produce(pool.Context(), func(job _Job) bool {
+		return pool.Go(func(ctx _context.Context) error {
+			part := point.done(job.Number)
+
+			if part == nil {
+				var err error
+				if part, err = bucket.WithContext(ctx).uploadPart(upload.UploadId, job, retry); err != nil {
+					return err
+				}
+				if err := point.save(part); err != nil {
+					return _fmt.Errorf("無法寫入檢查點 %s，Message：%w", point.state.File, err)
+				}
+			}
+
+			mutex.Lock()
+			parts = append(parts, part)
+			mutex.Unlock()
+			return nil
+		})
+	})
+
+	failed := pool.Wait()
+	if failed == nil && err != nil {
+		failed = err
+	}
+	if failed == nil && bucket.Context().Err() != nil {
+		failed = bucket.Context().Err()
+	}
+
+	if failed != nil && point != nil {
+		return _fmt.Errorf("分段上傳失敗，可透過檢查點 %s 續傳，Message：%w", point.state.File, failed)
+	}
+
+	if failed != nil {
+		bucket.WithContext(_context.Background()).AbortMultipart(upload.UploadId)
+		return _fmt.Errorf("分段上傳失敗，已取消此次上傳，Message：%w", failed)
+	}
+
+	_sort.Slice(parts, func(i, j int) bool { return parts[i].Number < parts[j].Number })
+
+	if err := bucket.CompleteMultipart(upload.UploadId, parts); err != nil {
+		if point == nil || isNotFound(err) {
+			point.remove()
+			bucket.WithContext(_context.Background()).AbortMultipart(upload.UploadId)
+		}
+		return err
+	}
+
+	point.remove()
+	return nil
+}
+
+func (bucket *Bucket) Upload(path string, args ...interface{}) error {
+	if bucket == nil {
+		return _err.New("錯誤的 Bucket")
+	}
+
+	if bucket.uri == "" {
+		return _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	path, err := _fs.Abs(path)
+	if err != nil {
+		return _err.New(_fmt.Sprintf("無法取得 %s 檔案的絕對位置，Message：%s", path, err))
+	}
+
+	stat, err := _os.Stat(path)
+	if err != nil {
+		return _err.New(_fmt.Sprintf("無法取得 %s 檔案狀態，Message：%s", path, err))
+	}
+
+	if !stat.Mode().IsRegular() {
+		return _err.New(_fmt.Sprintf("檔案 %s 不是常規的檔案", path))
+	}
+
+	size, _, _ := parseMultipart(args)
+	size, err = partSize(stat.Size(), size)
+	if err != nil {
+		return err
+	}
+
+	if uint64(stat.Size()) <= size {
+		return bucket.Put(path, args...)
+	}
+
+	var point *_Point
+	if dir := parseCheckpoint(args); dir != "" {
+		if point, err = bucket.checkpoint(dir, path, size); err != nil {
+			return err
+		}
+	}
+
+	file, err := _os.Open(path)
+	if err != nil {
+		return _err.New(_fmt.Sprintf("無法取得 %s 檔案的資源，Message：%s", path, err))
+	}
+	defer file.Close()
+
+	if _, _, cType := parseArgs(args); cType == "" {
+		if cType, err := getFileContentType(path); err == nil {
+			args = append(args, cType)
+		}
+	}
+
+	return bucket.multipart(point, func(ctx _context.Context, submit func(_Job) bool) error {
+		number := uint(1)
+		for offset := int64(0); offset < stat.Size(); offset += int64(size) {
+			length := int64(size)
+			if offset+length > stat.Size() {
+				length = stat.Size() - offset
+			}
+
+			if !submit(_Job{Number: number, Reader: _io.NewSectionReader(file, offset, length), Size: length}) {
+				return ctx.Err()
+			}
+			number++
+		}
+		return nil
+	}, args...)
+}
+func (bucket *Bucket) UploadReader(reader _io.Reader, size int64, args ...interface{}) error {
+	if bucket == nil {
+		return _err.New("錯誤的 Bucket")
+	}
+
+	if reader == nil {
+		return _err.New("錯誤的 Reader")
+	}
+
+	if bucket.uri == "" {
+		return _err.New(_fmt.Sprintf("S3 沒有指定路徑"))
+	}
+
+	part, _, _ := parseMultipart(args)
+	part, err := partSize(size, part)
+	if err != nil {
+		return err
+	}
+
+	if size >= 0 && uint64(size) <= part {
+		return bucket.PutReader(reader, size, args...)
+	}
+
+	head := make([]byte, part)
+	n, err := _io.ReadFull(reader, head)
+	switch {
+	case err == _io.EOF || err == _io.ErrUnexpectedEOF:
+		return bucket.PutBytes(head[:n], args...)
+	case err != nil:
+		return _err.New(_fmt.Sprintf("讀取資料失敗，Message：%s", err))
+	}
+
+	return bucket.multipart(nil, func(ctx _context.Context, submit func(_Job) bool) error {
+		buffer := head
+		for number := uint(1); ; number++ {
+			if number > PART_MAX_NUM {
+				return _err.New(_fmt.Sprintf("分段數量超過上限 %d，請調整分段大小", PART_MAX_NUM))
+			}
+
+			if buffer == nil {
+				buffer = make([]byte, part)
+				n, err = _io.ReadFull(reader, buffer)
+				if err == _io.EOF {
+					return nil
+				}
+				if err != nil && err != _io.ErrUnexpectedEOF {
+					return _err.New(_fmt.Sprintf("讀取資料失敗，Message：%s", err))
+				}
+			}
+
+			if !submit(_Job{Number: number, Reader: _bytes.NewReader(buffer[:n]), Size: int64(n)}) {
+				return ctx.Err()
+			}
+
+			if uint64(n) < part {
+				return nil
+			}
+			buffer = nil
+		}
+	}, args...)
+}
+func (bucket *Bucket) multipartCopy(src *Bucket, meta *_model.FileMeta, args ...interface{}) error {
+	size := uint64(PART_COPY_SIZE)
+	for _, arg := range args {
+		if val, ok := arg.(_Multipart); ok && val != nil && val.PartSizeNum() != nil {
+			size = *val.PartSizeNum()
+		}
+	}
+
+	size, err := partSize(int64(meta.ContentLength), size)
+	if err != nil {
+		return err
+	}
+
+	if copyDirective(args) == _enum.DIRECTIVE_COPY {
+		args = append(args, meta.ContentType, _MetaMap(meta.Meta), _Headers{
+			"Cache-Control":       meta.CacheControl,
+			"Content-Disposition": meta.ContentDisposition,
+			"Content-Encoding":    meta.ContentEncoding,
+			"Content-Language":    meta.ContentLanguage,
+			"Expires":             meta.Expires,
+		})
+	}
+
+	if _, _, cType := parseArgs(args); cType == "" && meta.ContentType != "" {
+		args = append(args, meta.ContentType)
+	}
+
+	if _, _, _, tagging := parseObjectArgs(args); tagging == "" {
+		tags, err := src.tags()
+		if err != nil {
+			return err
+		}
+		args = append(args, _TagsMap(tags))
+	}
+
+	total := int64(meta.ContentLength)
+
+	return bucket.multipart(nil, func(ctx _context.Context, submit func(_Job) bool) error {
+		number := uint(1)
+		for offset := int64(0); offset < total; offset += int64(size) {
+			length := int64(size)
+			if offset+length > total {
+				length = total - offset
+			}
+
+			if !submit(_Job{Number: number, Size: length, Source: src, ETag: meta.Md5, Offset: offset}) {
+				return ctx.Err()
+			}
+			number++
+		}
+		return nil
+	}, args...)
+}
@@ -0,0 +1,100 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package bucket
+
+import (
+	_fmt "fmt"
+	_http "net/http"
+	_enum "s3/enum"
+	_model "s3/model"
+	_testing "testing"
+)
+
+func TestPartSize(t *_testing.T) {
+	const MB = 1024 * 1024
+
+	tests := []struct {
+		name  string
+		total int64
+		size  uint64
+		want  uint64
+		fail  bool
+	}{
+		{"預設大小", 100 * MB, PART_DEFAULT_SIZE, PART_DEFAULT_SIZE, false},
+		{"小於下限", 100 * MB, 1 * MB, PART_MIN_SIZE, false},
+		{"長度未知", -1, 0, PART_MIN_SIZE, false},
+		{"剛好 10000 段", 10000 * 8 * MB, 8 * MB, 8 * MB, false},
+		{"超過 10000 段時放大並對齊 MB", 10000*8*MB + 1, 8 * MB, 9 * MB, false},
+		{"超過上限", 0, PART_MAX_SIZE + 1, 0, true},
+		{"檔案過大", PART_MAX_NUM*PART_MAX_SIZE + 1, 8 * MB, 0, true},
+	}
+
+	for _, test := range tests {
+		got, err := partSize(test.total, test.size)
+		if test.fail {
+			if err == nil {
+				t.Errorf("%s：預期錯誤，實際 %d", test.name, got)
+			}
+			continue
+		}
+		if err != nil || got != test.want {
+			t.Errorf("%s：預期 %d，實際 %d，錯誤 %v", test.name, test.want, got, err)
+		}
+	}
+}
+
+func copyFixture(t *_testing.T) (*Bucket, *Bucket, _http.Header) {
+	created := _http.Header{}
+
+	dest := stub(t, "bk/dest.bin", func(w _http.ResponseWriter, r *_http.Request) {
+		query := r.URL.Query()
+		switch {
+		case r.Method == _http.MethodGet && r.URL.Path == "/bk/src.bin":
+			_fmt.Fprint(w, "<Tagging><TagSet><Tag><Key>team</Key><Value>a b</Value></Tag></TagSet></Tagging>")
+		case r.Method == _http.MethodPost && query.Has("uploads"):
+			for key, vals := range r.Header {
+				created[key] = vals
+			}
+			_fmt.Fprint(w, "<InitiateMultipartUploadResult><UploadId>id</UploadId></InitiateMultipartUploadResult>")
+		case r.Method == _http.MethodPut:
+			_fmt.Fprint(w, "<CopyPartResult><ETag>\"etag\"</ETag></CopyPartResult>")
+		case r.Method == _http.MethodPost:
+			_fmt.Fprint(w, "<CompleteMultipartUploadResult><ETag>\"done-1\"</ETag></CompleteMultipartUploadResult>")
+		default:
+			w.WriteHeader(_http.StatusNotFound)
+		}
+	})
+
+	return dest.s3.Bucket("bk/src.bin"), dest, created
+}
+
+func TestMultipartCopy(t *_testing.T) {
+	meta := &_model.FileMeta{ContentLength: 10, Md5: "etag", ContentType: "image/png", CacheControl: "max-age=300", ContentDisposition: "inline", ContentEncoding: "gzip", ContentLanguage: "zh-TW", Expires: "Thu, 01 Dec 2030 16:00:00 GMT", Meta: map[string]string{"owner": "src"}}
+
+	tests := []struct {
+		name string
+		args []interface{}
+		want map[string]string
+	}{
+		{"COPY 沿用來源", []interface{}{_enum.ACL_PUBLIC_READ}, map[string]string{"Content-Type": "image/png", "Cache-Control": "max-age=300", "Content-Disposition": "inline", "Content-Encoding": "gzip", "Content-Language": "zh-TW", "Expires": "Thu, 01 Dec 2030 16:00:00 GMT", "X-Amz-Meta-Owner": "src", "X-Amz-Tagging": "team=a+b"}},
+		{"REPLACE 套用參數", []interface{}{_enum.DIRECTIVE_REPLACE, _Cache("no-cache"), _MetaMap{"owner": "caller"}}, map[string]string{"Content-Type": "image/png", "Cache-Control": "no-cache", "Content-Disposition": "", "X-Amz-Meta-Owner": "caller", "X-Amz-Tagging": "team=a+b"}},
+		{"指定標籤", []interface{}{_TagsMap{"env": "dev"}}, map[string]string{"X-Amz-Tagging": "env=dev"}},
+	}
+
+	for _, test := range tests {
+		src, dest, created := copyFixture(t)
+		if err := dest.multipartCopy(src, meta, test.args...); err != nil {
+			t.Fatalf("%s：%v", test.name, err)
+		}
+		for key, want := range test.want {
+			if got := created.Get(key); got != want {
+				t.Errorf("%s：%s 預期 %q，實際 %q", test.name, key, want, got)
+			}
+		}
+	}
+}
@@ -0,0 +1,29 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package enum
+
+type Directive int
+
+const (
+	DIRECTIVE_COPY Directive = iota
+	DIRECTIVE_REPLACE
+)
+
+func (directive Directive) Str() string {
+	switch directive {
+	case DIRECTIVE_REPLACE:
+		return "REPLACE"
+	default:
+		return "COPY"
+	}
+}
+
+// https://docs.aws.amazon.com/AmazonS3/latest/API/API_CopyObject.html
+
+// DIRECTIVE_COPY    | Copies the metadata from the source object (default).
+// DIRECTIVE_REPLACE | Replaces the metadata with the metadata provided in the request.
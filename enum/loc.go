@@ -94,3 +94,13 @@ func (loc Loc) Str() string {
 		return ""
 	}
 }
+func (loc Loc) Region() string {
+	switch loc {
+	case LOC_NANO:
+		return "us-east-1"
+	case LOC_EU:
+		return "eu-west-1"
+	default:
+		return loc.Str()
+	}
+}
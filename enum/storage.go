@@ -0,0 +1,53 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package enum
+
+type Storage int
+
+const (
+	STORAGE_STANDARD Storage = iota
+	STORAGE_REDUCED_REDUNDANCY
+	STORAGE_STANDARD_IA
+	STORAGE_ONEZONE_IA
+	STORAGE_INTELLIGENT_TIERING
+	STORAGE_GLACIER
+	STORAGE_GLACIER_IR
+	STORAGE_DEEP_ARCHIVE
+)
+
+func (storage Storage) Str() string {
+	switch storage {
+	case STORAGE_REDUCED_REDUNDANCY:
+		return "REDUCED_REDUNDANCY"
+	case STORAGE_STANDARD_IA:
+		return "STANDARD_IA"
+	case STORAGE_ONEZONE_IA:
+		return "ONEZONE_IA"
+	case STORAGE_INTELLIGENT_TIERING:
+		return "INTELLIGENT_TIERING"
+	case STORAGE_GLACIER:
+		return "GLACIER"
+	case STORAGE_GLACIER_IR:
+		return "GLACIER_IR"
+	case STORAGE_DEEP_ARCHIVE:
+		return "DEEP_ARCHIVE"
+	default:
+		return "STANDARD"
+	}
+}
+
+// https://docs.aws.amazon.com/AmazonS3/latest/userguide/storage-class-intro.html
+
+// STORAGE_STANDARD            | Frequently accessed data (default).
+// STORAGE_REDUCED_REDUNDANCY  | Noncritical, frequently accessed data (not recommended).
+// STORAGE_STANDARD_IA         | Long-lived, infrequently accessed data.
+// STORAGE_ONEZONE_IA          | Long-lived, infrequently accessed, non-critical data stored in a single Availability Zone.
+// STORAGE_INTELLIGENT_TIERING | Data with unknown or changing access patterns.
+// STORAGE_GLACIER             | Long-term archive data, retrieval in minutes to hours.
+// STORAGE_GLACIER_IR          | Long-term archive data that needs milliseconds retrieval.
+// STORAGE_DEEP_ARCHIVE        | Long-term archive data, retrieval within 12 hours.
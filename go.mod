@@ -1,3 +1,3 @@
 module github.com/oawu/Golang-S3
 
-go 1.18
+go 1.20
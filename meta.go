@@ -0,0 +1,15 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package s3
+
+type Meta map[string]string
+
+func (meta Meta) GetMetaInterface() {}
+func (meta Meta) MetaMap() map[string]string {
+	return meta
+}
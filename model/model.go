@@ -21,14 +21,72 @@ type BucketInfoBucket struct {
 	Time uint64
 }
 type File struct {
-	Key  string
-	Time uint64
-	Md5  string
-	Size uint64
+	Key       string
+	Time      uint64
+	Md5       string
+	Size      uint64
+	OwnerId   string
+	OwnerName string
+}
+type Listing struct {
+	Files       []*File
+	Prefixes    []string
+	NextToken   string
+	IsTruncated bool
 }
 type FileMeta struct {
-	ContentLength uint64
-	Time          uint64
-	Md5           string
-	ContentType   string
+	ContentLength      uint64
+	Time               uint64
+	Md5                string
+	ContentType        string
+	CacheControl       string
+	ContentDisposition string
+	ContentEncoding    string
+	ContentLanguage    string
+	Expires            string
+	Meta               map[string]string
+	IsMd5              bool
+}
+type ContentRange struct {
+	Start uint64
+	End   uint64
+	Total uint64
+}
+type PostForm struct {
+	Url    string
+	Fields map[string]string
+}
+type Upload struct {
+	Key      string
+	UploadId string
+	Time     uint64
+}
+type Part struct {
+	Number uint
+	ETag   string
+	Size   uint64
+	Time   uint64
+}
+type Checkpoint struct {
+	Bucket   string
+	Key      string
+	UploadId string
+	Path     string
+	Size     uint64
+	ModTime  int64
+	Hash     string
+	PartSize uint64
+	Parts    []*Part
+	Time     uint64
+	File     string `json:"-"`
+}
+type Failure struct {
+	Key   string
+	Error error
+}
+type Report struct {
+	Total     uint64
+	Success   uint64
+	Succeeded []string
+	Failures  []*Failure
 }
@@ -0,0 +1,48 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package s3
+
+type Multipart struct {
+	PartSize   uint64
+	Goroutines uint8
+	Retry      uint8
+	Checkpoint string
+	Rate       uint
+}
+
+func (multipart Multipart) GetMultipartInterface() {}
+func (multipart Multipart) PartSizeNum() *uint64 {
+	if multipart.PartSize == 0 {
+		return nil
+	}
+	return &multipart.PartSize
+}
+func (multipart Multipart) GoroutinesNum() *uint8 {
+	if multipart.Goroutines == 0 {
+		return nil
+	}
+	return &multipart.Goroutines
+}
+func (multipart Multipart) RetryNum() *uint8 {
+	if multipart.Retry == 0 {
+		return nil
+	}
+	return &multipart.Retry
+}
+func (multipart Multipart) CheckpointStr() *string {
+	if multipart.Checkpoint == "" {
+		return nil
+	}
+	return &multipart.Checkpoint
+}
+func (multipart Multipart) RateNum() *uint {
+	if multipart.Rate == 0 {
+		return nil
+	}
+	return &multipart.Rate
+}
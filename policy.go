@@ -0,0 +1,42 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package s3
+
+import (
+	_enum "s3/enum"
+)
+
+type Policy struct {
+	Acl         _enum.Acl
+	ContentType string
+	MinSize     uint64
+	MaxSize     uint64
+}
+
+func (policy Policy) GetPolicyInterface() {}
+func (policy Policy) AclVal() _enum.Acl {
+	return policy.Acl
+}
+func (policy Policy) ContentTypeStr() *string {
+	if policy.ContentType == "" {
+		return nil
+	}
+	return &policy.ContentType
+}
+func (policy Policy) MinSizeNum() *uint64 {
+	if policy.MinSize == 0 {
+		return nil
+	}
+	return &policy.MinSize
+}
+func (policy Policy) MaxSizeNum() *uint64 {
+	if policy.MaxSize == 0 {
+		return nil
+	}
+	return &policy.MaxSize
+}
@@ -0,0 +1,56 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package s3
+
+type Prune struct {
+	Prefix    string
+	OlderThan uint
+	MinSize   uint64
+	MaxSize   uint64
+	Pattern   string
+	DryRun    bool
+	Progress  func(done uint64, total uint64)
+}
+
+func (prune Prune) GetPruneInterface() {}
+func (prune Prune) PrefixStr() *string {
+	if prune.Prefix == "" {
+		return nil
+	}
+	return &prune.Prefix
+}
+func (prune Prune) OlderThanNum() *uint {
+	if prune.OlderThan == 0 {
+		return nil
+	}
+	return &prune.OlderThan
+}
+func (prune Prune) MinSizeNum() *uint64 {
+	if prune.MinSize == 0 {
+		return nil
+	}
+	return &prune.MinSize
+}
+func (prune Prune) MaxSizeNum() *uint64 {
+	if prune.MaxSize == 0 {
+		return nil
+	}
+	return &prune.MaxSize
+}
+func (prune Prune) PatternStr() *string {
+	if prune.Pattern == "" {
+		return nil
+	}
+	return &prune.Pattern
+}
+func (prune Prune) IsDryRun() bool {
+	return prune.DryRun
+}
+func (prune Prune) ProgressFunc() func(done uint64, total uint64) {
+	return prune.Progress
+}
@@ -8,9 +8,16 @@
 package request
 
 import (
+	_context "context"
+	_sha256 "crypto/sha256"
+	_base64 "encoding/base64"
+	_hex "encoding/hex"
+	_json "encoding/json"
 	_err "errors"
 	_fmt "fmt"
+	_io "io"
 	_ioutil "io/ioutil"
+	_net "net"
 	_http "net/http"
 	_url "net/url"
 	_os "os"
@@ -19,6 +26,7 @@ import (
 	_resp "s3/request/response"
 	_sort "sort"
 	_str "strings"
+	_syscall "syscall"
 	_time "time"
 )
 
@@ -30,6 +38,11 @@ type _File struct {
 	Path string
 	Size uint64
 }
+type _Reader struct {
+	Reader _io.Reader
+	Size   uint64
+	Offset int64
+}
 type _Request struct {
 	s3         _S3
 	method     _enum.Method
@@ -37,19 +50,128 @@ type _Request struct {
 	bucket     string
 	data       *_Data
 	file       *_File
+	reader     *_Reader
 	parameters map[string]string
 	useSSL     bool
 	headers    map[string]string
 	amzHeaders map[string]string
+	time       _time.Time
+	ctx        _context.Context
+	stream     bool
 }
 
 type _S3 interface {
 	GetS3Interface()
 	Signature(str string) string
+	SignatureV2(str string) string
+	SignatureV4(date string, region string, service string, str string) string
+	AccessKey() string
+	IsV4() bool
+	RegionStr() string
+	BucketRegionStr(bucket string) string
+	SetBucketRegion(bucket string, region string)
+	HttpClient() *_http.Client
+	RetryAttempts() uint
+	RetryDelay(attempt uint) _time.Duration
+	SchemeStr() string
+	HostStr() string
+	IsPathStyle() bool
+}
+
+var (
+	_client = newClient()
+)
+
+const (
+	HOST      = "s3.amazonaws.com"
+	SERVICE   = "s3"
+	ALGORITHM = "AWS4-HMAC-SHA256"
+)
+
+func newClient() *_http.Client {
+	transport := _http.DefaultTransport.(*_http.Transport).Clone()
+	transport.MaxIdleConns = 256
+	transport.MaxIdleConnsPerHost = 64
+	return &_http.Client{Transport: transport}
+}
+func hashSha256(data []byte) string {
+	hash := _sha256.Sum256(data)
+	return _hex.EncodeToString(hash[:])
+}
+func regionHost(region string) string {
+	switch {
+	case region == "" || region == "us-east-1":
+		return HOST
+	case _str.HasPrefix(region, "cn-"):
+		return _fmt.Sprintf("s3.%s.amazonaws.com.cn", region)
+	default:
+		return _fmt.Sprintf("s3.%s.amazonaws.com", region)
+	}
+}
+
+func gmt(time _time.Time) string {
+	return time.In(_time.FixedZone("GMT", 0)).Format("Mon, 2 Jan 2006 15:04:05 GMT")
+}
+func errorOf(resp *_resp.Response) (string, string) {
+	err := resp.S3Error()
+	if err == nil {
+		return "", ""
+	}
+
+	return err.Code, err.Region
 }
+func regionOf(resp *_resp.Response) string {
+	if resp == nil || resp.Error != nil {
+		return ""
+	}
+
+	switch resp.StatusCode {
+	case 301, 307, 400:
+	default:
+		return ""
+	}
 
-const HOST = "s3.amazonaws.com"
+	if region, ok := resp.Headers["X-Amz-Bucket-Region"]; ok && region != "" {
+		return region
+	}
 
+	switch code, region := errorOf(resp); code {
+	case "PermanentRedirect", "TemporaryRedirect", "AuthorizationHeaderMalformed", "IllegalLocationConstraintException":
+		return region
+	default:
+		return ""
+	}
+}
+func retryable(resp *_resp.Response) bool {
+	if resp == nil {
+		return false
+	}
+
+	if resp.Error != nil {
+		if _err.Is(resp.Error, _context.Canceled) || _err.Is(resp.Error, _context.DeadlineExceeded) {
+			return false
+		}
+
+		var netErr _net.Error
+		if _err.As(resp.Error, &netErr) && netErr.Timeout() {
+			return true
+		}
+
+		return _err.Is(resp.Error, _syscall.ECONNRESET) || _err.Is(resp.Error, _syscall.ECONNREFUSED) || _err.Is(resp.Error, _io.EOF) || _err.Is(resp.Error, _io.ErrUnexpectedEOF)
+	}
+
+	switch resp.StatusCode {
+	case 500, 502, 503, 504:
+		return true
+	}
+
+	switch code, _ := errorOf(resp); code {
+	case "InternalError", "RequestTimeout", "SlowDown", "ServiceUnavailable", "Throttling", "ThrottlingException", "RequestThrottled":
+		return true
+	default:
+		return false
+	}
+}
 func rawurlencode(str string) string {
 	return _str.Replace(_url.QueryEscape(str), "+", "%20", -1)
 }
@@ -57,10 +179,11 @@ func New(s3 _S3) *_Request {
 	if s3 == nil {
 		return nil
 	}
+	now := _time.Now()
 	parameters := map[string]string{}
 	headers := map[string]string{
-		"Host":         HOST,
-		"Date":         _time.Now().In(_time.FixedZone("GMT", 0)).Format("Mon, 2 Jan 2006 15:04:05 GMT"),
+		"Host":         "",
+		"Date":         gmt(now),
 		"Content-MD5":  "",
 		"Content-Type": "",
 	}
@@ -73,11 +196,15 @@ func New(s3 _S3) *_Request {
 		bucket:     "",
 		data:       nil,
 		file:       nil,
+		reader:     nil,
 		useSSL:     false,
 		parameters: parameters,
 		headers:    headers,
-		amzHeaders: amzHeaders}
+		amzHeaders: amzHeaders,
+		time:       now,
+		ctx:        _context.Background()}
 
+	req.headers["Host"] = req.host()
 	return req
 }
 
@@ -88,7 +215,7 @@ func (req *_Request) SetHeader(key, val string) *_Request {
 	return req
 }
 func (req *_Request) SetAmzHeader(key, val string) *_Request {
-	if req == nil && val != "" {
+	if req == nil || val == "" {
 		return req
 	}
 
@@ -98,10 +225,10 @@ func (req *_Request) SetAmzHeader(key, val string) *_Request {
 		return req
 	}
 
-	if len(regx.FindStringSubmatch(key)) > 0 {
+	if len(regx.FindStringSubmatch(_str.ToLower(key))) > 0 {
 		req.amzHeaders[key] = val
 	} else {
-		req.amzHeaders[key] = _fmt.Sprintf("x-amz-meta-%s", val)
+		req.amzHeaders[_fmt.Sprintf("x-amz-meta-%s", key)] = val
 	}
 
 	return req
@@ -118,6 +245,21 @@ func (req *_Request) SetFile(path string, size uint64) *_Request {
 	}
 	return req
 }
+func (req *_Request) SetReader(reader _io.Reader, size uint64) *_Request {
+	if req == nil || reader == nil {
+		return req
+	}
+
+	offset := int64(-1)
+	if seeker, ok := reader.(_io.Seeker); ok {
+		if current, err := seeker.Seek(0, _io.SeekCurrent); err == nil {
+			offset = current
+		}
+	}
+
+	req.reader = &_Reader{Reader: reader, Size: size, Offset: offset}
+	return req
+}
 func (req *_Request) Parameter(key string, val string) *_Request {
 	if req == nil || key == "" {
 		return req
@@ -134,50 +276,132 @@ func (req *_Request) Response() *_resp.Response {
 		return resp.GG(_err.New("錯誤的 Request"))
 	}
 
-	uri := req.uri
-	resource := req.uri
+	for attempt := uint(1); ; attempt++ {
+		resp = req.redirect(resp.Reset())
 
-	queries := []string{}
-	for key, val := range req.parameters {
-		queries = append(queries, _fmt.Sprintf("%s=%s", key, rawurlencode(val)))
+		if attempt >= req.s3.RetryAttempts() || req.ctx.Err() != nil || !retryable(resp) || !req.rewind() {
+			return resp
+		}
+
+		timer := _time.NewTimer(req.s3.RetryDelay(attempt))
+		select {
+		case <-req.ctx.Done():
+			timer.Stop()
+			return resp
+		case <-timer.C:
+		}
+
+		req.time = _time.Now()
+		req.headers["Date"] = gmt(req.time)
+	}
+}
+func (req *_Request) Presign(expire _time.Duration) (string, error) {
+	if req == nil {
+		return "", _err.New("錯誤的 Request")
 	}
 
-	if query := _str.Join(queries, "&"); query != "" {
-		uri = _fmt.Sprintf("%s?%s", req.uri, query)
+	if expire < _time.Second {
+		return "", _err.New("有效時間至少需要 1 秒")
 	}
 
-	sepQueries := []string{}
-	for _, key := range []string{"acl", "location", "torrent", "logging"} {
-		if val, ok := req.parameters[key]; ok {
-			sepQueries = append(sepQueries, _fmt.Sprintf("%s=%s", key, rawurlencode(val)))
+	if !req.s3.IsV4() {
+		expires := _fmt.Sprintf("%d", req.time.Add(expire).Unix())
+		signature := req.s3.SignatureV2(req.stringToSign(expires, req.resource()))
+
+		req.Parameter("AWSAccessKeyId", req.s3.AccessKey())
+		req.Parameter("Expires", expires)
+		req.Parameter("Signature", signature)
+
+		return req.url(), nil
+	}
+
+	if expire > 7*24*_time.Hour {
+		return "", _err.New("Signature V4 的有效時間最多 7 天")
+	}
+
+	headers := req.headersV4()
+
+	region := req.region()
+	amzDate := req.time.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	scope := _fmt.Sprintf("%s/%s/%s/aws4_request", date, region, SERVICE)
+	canonical, signed := canonicalHeaders(headers)
+
+	req.Parameter("X-Amz-Algorithm", ALGORITHM)
+	req.Parameter("X-Amz-Credential", _fmt.Sprintf("%s/%s", req.s3.AccessKey(), scope))
+	req.Parameter("X-Amz-Date", amzDate)
+	req.Parameter("X-Amz-Expires", _fmt.Sprintf("%d", int64(expire/_time.Second)))
+	req.Parameter("X-Amz-SignedHeaders", signed)
+
+	str := req.stringToSignV4(amzDate, scope, canonical, signed, "UNSIGNED-PAYLOAD")
+	req.Parameter("X-Amz-Signature", req.s3.SignatureV4(date, region, SERVICE, str))
+
+	return req.url(), nil
+}
+func (req *_Request) PresignPost(expire _time.Duration, conditions []interface{}, fields map[string]string) (string, map[string]string, error) {
+	if req == nil {
+		return "", nil, _err.New("錯誤的 Request")
+	}
+
+	if req.bucket == "" {
+		return "", nil, _err.New("沒有指定 Bucket")
+	}
+
+	if expire < _time.Second {
+		return "", nil, _err.New("有效時間至少需要 1 秒")
+	}
+
+	form := map[string]string{}
+	for key, val := range fields {
+		form[key] = val
+	}
+
+	conditions = append(conditions, map[string]string{"bucket": req.bucket})
+
+	region := req.region()
+	amzDate := req.time.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	if req.s3.IsV4() {
+		form["x-amz-algorithm"] = ALGORITHM
+		form["x-amz-credential"] = _fmt.Sprintf("%s/%s/%s/%s/aws4_request", req.s3.AccessKey(), date, region, SERVICE)
+		form["x-amz-date"] = amzDate
+
+		for _, key := range []string{"x-amz-algorithm", "x-amz-credential", "x-amz-date"} {
+			conditions = append(conditions, map[string]string{key: form[key]})
 		}
 	}
 
-	sepQuery := _str.Join(sepQueries, "&")
-	switch {
-	case req.bucket == "" && sepQuery == "":
-		resource = _fmt.Sprintf("/%s", req.uri)
-	case req.bucket != "" && sepQuery == "":
-		resource = _fmt.Sprintf("/%s/%s", req.bucket, req.uri)
-	case req.bucket != "" && sepQuery != "":
-		resource = _fmt.Sprintf("/%s/%s?%s", req.bucket, req.uri, sepQuery)
-	case req.bucket == "" && sepQuery != "":
-		resource = _fmt.Sprintf("/%s?%s", req.uri, sepQuery)
+	document, err := _json.Marshal(struct {
+		Expiration string        `json:"expiration"`
+		Conditions []interface{} `json:"conditions"`
+	}{
+		Expiration: req.time.Add(expire).UTC().Format("2006-01-02T15:04:05.000Z"),
+		Conditions: conditions,
+	})
+
+	if err != nil {
+		return "", nil, _err.New(_fmt.Sprintf("產生 Policy 失敗，Message：%s", err))
 	}
 
-	protocol := "http"
-	if req.useSSL {
-		protocol = "https"
+	policy := _base64.StdEncoding.EncodeToString(document)
+	form["policy"] = policy
+
+	if req.s3.IsV4() {
+		form["x-amz-signature"] = req.s3.SignatureV4(date, region, SERVICE, policy)
+	} else {
+		form["AWSAccessKeyId"] = req.s3.AccessKey()
+		form["signature"] = req.s3.SignatureV2(policy)
 	}
 
-	return req.send(_fmt.Sprintf("%s://%s/%s", protocol, req.headers["Host"], uri), req.makeHeader(resource), resp)
+	return req.url(), form, nil
 }
 func (req *_Request) Uri(uri string) *_Request {
 	if req == nil || uri == "" {
 		return req
 	}
 
-	req.uri = _str.Trim(_str.Replace(rawurlencode(uri), "%2F", "/", -1), "/")
+	req.uri = _str.TrimLeft(_str.Replace(rawurlencode(uri), "%2F", "/", -1), "/")
 	return req
 }
 func (req *_Request) Bucket(bucket string) *_Request {
@@ -185,13 +409,22 @@ func (req *_Request) Bucket(bucket string) *_Request {
 		return req
 	}
 	req.bucket = _str.ToLower(bucket)
+	req.headers["Host"] = req.host()
 
-	if req.bucket != "" {
-		req.headers["Host"] = _fmt.Sprintf("%s.%s", req.bucket, HOST)
-	} else {
-		req.headers["Host"] = HOST
+	return req
+}
+func (req *_Request) Context(ctx _context.Context) *_Request {
+	if req == nil || ctx == nil {
+		return req
 	}
-
+	req.ctx = ctx
+	return req
+}
+func (req *_Request) Stream(stream bool) *_Request {
+	if req == nil {
+		return req
+	}
+	req.stream = stream
 	return req
 }
 func (req *_Request) Method(method _enum.Method) *_Request {
@@ -209,12 +442,127 @@ func (req *_Request) UseSSL(useSSL bool) *_Request {
 	return req
 }
 
+func (req *_Request) redirect(resp *_resp.Response) *_resp.Response {
+	for redirected := false; ; redirected = true {
+		region := req.region()
+		resp = req.send(req.url(), req.makeHeader(req.resource()), resp.Reset())
+
+		if redirected || req.bucket == "" || req.s3.HostStr() != "" {
+			return resp
+		}
+
+		found := regionOf(resp)
+		if found == "" || found == region {
+			return resp
+		}
+
+		req.s3.SetBucketRegion(req.bucket, found)
+		req.headers["Host"] = req.host()
+	}
+}
+func (req *_Request) region() string {
+	if req.bucket == "" {
+		return req.s3.RegionStr()
+	}
+	return req.s3.BucketRegionStr(req.bucket)
+}
+func (req *_Request) host() string {
+	host := req.s3.HostStr()
+	if host == "" {
+		host = regionHost(req.region())
+	}
+
+	if req.bucket == "" || req.s3.IsPathStyle() {
+		return host
+	}
+
+	return _fmt.Sprintf("%s.%s", req.bucket, host)
+}
+func (req *_Request) path() string {
+	if req.bucket == "" || !req.s3.IsPathStyle() {
+		return _fmt.Sprintf("/%s", req.uri)
+	}
+
+	if req.uri == "" {
+		return _fmt.Sprintf("/%s", req.bucket)
+	}
+
+	return _fmt.Sprintf("/%s/%s", req.bucket, req.uri)
+}
+func (req *_Request) url() string {
+	uri := req.path()
+	if query := req.canonicalQuery(); query != "" {
+		uri = _fmt.Sprintf("%s?%s", uri, query)
+	}
+
+	protocol := req.s3.SchemeStr()
+	switch {
+	case protocol != "":
+	case req.useSSL:
+		protocol = "https"
+	default:
+		protocol = "http"
+	}
+
+	return _fmt.Sprintf("%s://%s%s", protocol, req.headers["Host"], uri)
+}
+func (req *_Request) resource() string {
+	sepQueries := []string{}
+	for _, key := range []string{"acl", "cors", "delete", "lifecycle", "location", "logging", "notification", "partNumber", "policy", "requestPayment", "tagging", "torrent", "uploadId", "uploads", "versionId", "versioning", "versions", "website"} {
+		if val, ok := req.parameters[key]; ok && val == "" {
+			sepQueries = append(sepQueries, key)
+		} else if ok {
+			sepQueries = append(sepQueries, _fmt.Sprintf("%s=%s", key, rawurlencode(val)))
+		}
+	}
+
+	sepQuery := _str.Join(sepQueries, "&")
+	switch {
+	case req.bucket != "" && sepQuery == "":
+		return _fmt.Sprintf("/%s/%s", req.bucket, req.uri)
+	case req.bucket != "" && sepQuery != "":
+		return _fmt.Sprintf("/%s/%s?%s", req.bucket, req.uri, sepQuery)
+	case req.bucket == "" && sepQuery != "":
+		return _fmt.Sprintf("/%s?%s", req.uri, sepQuery)
+	default:
+		return _fmt.Sprintf("/%s", req.uri)
+	}
+}
+func (req *_Request) stringToSign(date string, resource string) string {
+	tokens := []string{req.method.Str()}
+	for _, key := range []string{"Content-MD5", "Content-Type"} {
+		if val, ok := req.headers[key]; ok {
+			tokens = append(tokens, val)
+		}
+	}
+	tokens = append(tokens, date)
+
+	amzs := []string{}
+	for key, header := range req.amzHeaders {
+		if header != "" {
+			amzs = append(amzs, _fmt.Sprintf("%s:%s", _str.ToLower(key), header))
+		}
+	}
+
+	if len(amzs) > 0 {
+		_sort.Strings(amzs)
+		for _, amz := range amzs {
+			tokens = append(tokens, amz)
+		}
+	}
+
+	return _str.Join(append(tokens, resource), "\n")
+}
 func (req *_Request) makeHeader(resource string) map[string]string {
 	headers := map[string]string{}
 	if req == nil {
 		return headers
 	}
 
+	if req.s3.IsV4() {
+		return req.makeHeaderV4()
+	}
+
 	for key, header := range req.amzHeaders {
 		if header != "" {
 			headers[key] = header
@@ -226,28 +574,117 @@ func (req *_Request) makeHeader(resource string) map[string]string {
 		}
 	}
 
-	tokens := []string{req.method.Str()}
-	for _, key := range []string{"Content-MD5", "Content-Type", "Date"} {
-		if val, ok := req.headers[key]; ok {
-			tokens = append(tokens, val)
+	headers["Authorization"] = req.s3.Signature(req.stringToSign(req.headers["Date"], resource))
+	return headers
+}
+
+func (req *_Request) payloadHash() string {
+	switch {
+	case req.file != nil:
+		data, err := _os.Open(req.file.Path)
+		if err != nil {
+			return "UNSIGNED-PAYLOAD"
+		}
+		defer data.Close()
+
+		hash := _sha256.New()
+		if _, err := _io.Copy(hash, data); err != nil {
+			return "UNSIGNED-PAYLOAD"
+		}
+		return _hex.EncodeToString(hash.Sum(nil))
+	case req.data != nil:
+		return hashSha256([]byte(req.data.Str))
+	case req.reader != nil:
+		if !req.rewind() {
+			return "UNSIGNED-PAYLOAD"
+		}
+
+		hash := _sha256.New()
+		if _, err := _io.CopyN(hash, req.reader.Reader, int64(req.reader.Size)); err != nil || !req.rewind() {
+			return "UNSIGNED-PAYLOAD"
 		}
+		return _hex.EncodeToString(hash.Sum(nil))
+	default:
+		return hashSha256([]byte{})
+	}
+}
+func (req *_Request) rewind() bool {
+	if req.reader == nil {
+		return true
 	}
 
-	amzs := []string{}
+	seeker, ok := req.reader.Reader.(_io.Seeker)
+	if !ok || req.reader.Offset < 0 {
+		return false
+	}
+
+	_, err := seeker.Seek(req.reader.Offset, _io.SeekStart)
+	return err == nil
+}
+func canonicalHeaders(headers map[string]string) (string, string) {
+	keys := []string{}
+	for key := range headers {
+		keys = append(keys, key)
+	}
+	_sort.Strings(keys)
+
+	tokens := []string{}
+	for _, key := range keys {
+		tokens = append(tokens, _fmt.Sprintf("%s:%s\n", key, _str.Join(_str.Fields(headers[key]), " ")))
+	}
+
+	return _str.Join(tokens, ""), _str.Join(keys, ";")
+}
+func (req *_Request) canonicalQuery() string {
+	keys := []string{}
+	for key := range req.parameters {
+		keys = append(keys, key)
+	}
+	_sort.Strings(keys)
+
+	queries := []string{}
+	for _, key := range keys {
+		queries = append(queries, _fmt.Sprintf("%s=%s", rawurlencode(key), rawurlencode(req.parameters[key])))
+	}
+	return _str.Join(queries, "&")
+}
+func (req *_Request) stringToSignV4(amzDate string, scope string, canonical string, signed string, payload string) string {
+	request := _str.Join([]string{req.method.Str(), req.path(), req.canonicalQuery(), canonical, signed, payload}, "\n")
+	return _str.Join([]string{ALGORITHM, amzDate, scope, hashSha256([]byte(request))}, "\n")
+}
+func (req *_Request) headersV4() map[string]string {
+	headers := map[string]string{}
 	for key, header := range req.amzHeaders {
 		if header != "" {
-			amzs = append(amzs, _fmt.Sprintf("%s:%s", _str.ToLower(key), header))
+			headers[_str.ToLower(key)] = header
 		}
 	}
-
-	if len(amzs) > 0 {
-		_sort.Strings(amzs)
-		for _, amz := range amzs {
-			tokens = append(tokens, amz)
+	for key, header := range req.headers {
+		if header != "" && key != "Date" {
+			headers[_str.ToLower(key)] = header
 		}
 	}
+	return headers
+}
+func (req *_Request) makeHeaderV4() map[string]string {
+	if req == nil {
+		return map[string]string{}
+	}
 
-	headers["Authorization"] = req.s3.Signature(_str.Join(append(tokens, resource), "\n"))
+	headers := req.headersV4()
+
+	region := req.region()
+	amzDate := req.time.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+	scope := _fmt.Sprintf("%s/%s/%s/aws4_request", date, region, SERVICE)
+
+	headers["x-amz-date"] = amzDate
+	headers["x-amz-content-sha256"] = req.payloadHash()
+
+	canonical, signed := canonicalHeaders(headers)
+	str := req.stringToSignV4(amzDate, scope, canonical, signed, headers["x-amz-content-sha256"])
+
+	headers["Authorization"] = _fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s", ALGORITHM, req.s3.AccessKey(), scope, signed, req.s3.SignatureV4(date, region, SERVICE, str))
 	return headers
 }
 
@@ -265,11 +702,16 @@ func (req *_Request) send(url string, headers map[string]string, resp *_resp.Res
 			return resp.GG(e)
 		}
 		defer data.Close()
-		r, err = _http.NewRequest(req.method.Str(), url, data)
+		r, err = _http.NewRequestWithContext(req.ctx, req.method.Str(), url, data)
 	case req.data != nil:
-		r, err = _http.NewRequest(req.method.Str(), url, _str.NewReader(req.data.Str))
+		r, err = _http.NewRequestWithContext(req.ctx, req.method.Str(), url, _str.NewReader(req.data.Str))
+	case req.reader != nil && req.reader.Size == 0:
+		r, err = _http.NewRequestWithContext(req.ctx, req.method.Str(), url, _http.NoBody)
+	case req.reader != nil:
+		req.rewind()
+		r, err = _http.NewRequestWithContext(req.ctx, req.method.Str(), url, _io.NopCloser(_io.LimitReader(req.reader.Reader, int64(req.reader.Size))))
 	default:
-		r, err = _http.NewRequest(req.method.Str(), url, nil)
+		r, err = _http.NewRequestWithContext(req.ctx, req.method.Str(), url, nil)
 	}
 
 	if err != nil {
@@ -281,6 +723,9 @@ func (req *_Request) send(url string, headers map[string]string, resp *_resp.Res
 	if req.file != nil {
 		r.ContentLength = int64(req.file.Size)
 	}
+	if req.reader != nil {
+		r.ContentLength = int64(req.reader.Size)
+	}
 	r.Header = _http.Header{
 		"User-Agent": {"S3/Golang"},
 	}
@@ -288,35 +733,37 @@ func (req *_Request) send(url string, headers map[string]string, resp *_resp.Res
 		r.Header.Add(key, val)
 	}
 
-	client := &_http.Client{
-		CheckRedirect: func(req *_http.Request, vias []*_http.Request) error {
-			if len(vias) >= 10 {
-				return _err.New("轉導次數過多")
-			}
-			if len(vias) == 0 {
-				return nil
-			}
+	client := *_client
+	if custom := req.s3.HttpClient(); custom != nil {
+		client = *custom
+	}
 
-			via := vias[0]
+	useV4 := req.s3.IsV4()
+	client.CheckRedirect = func(req *_http.Request, vias []*_http.Request) error {
+		if useV4 {
+			return _http.ErrUseLastResponse
+		}
+		if len(vias) >= 10 {
+			return _err.New("轉導次數過多")
+		}
+		if len(vias) == 0 {
+			return nil
+		}
 
-			req.Header = _http.Header{}
-			for attr, val := range via.Header {
-				req.Header[attr] = val
-			}
+		via := vias[0]
 
-			return nil
-		},
+		req.Header = _http.Header{}
+		for attr, val := range via.Header {
+			req.Header[attr] = val
+		}
+
+		return nil
 	}
 
 	result, err := client.Do(r)
 	if err != nil {
 		return resp.GG(err)
 	}
-	defer result.Body.Close()
-	sitemap, err := _ioutil.ReadAll(result.Body)
-	if err != nil {
-		return resp.GG(err)
-	}
 
 	resp.StatusCode = uint16(result.StatusCode)
 	for key, header := range result.Header {
@@ -324,10 +771,22 @@ func (req *_Request) send(url string, headers map[string]string, resp *_resp.Res
 			resp.Headers[key] = header[0]
 		}
 	}
+
+	if req.stream && resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		resp.Body = result.Body
+		return resp
+	}
+
+	defer result.Body.Close()
+	sitemap, err := _ioutil.ReadAll(result.Body)
+	if err != nil {
+		return resp.GG(err)
+	}
+
 	resp.BodyBytes = sitemap
 	resp.BodyString = string(sitemap)
 
-	if resp.StatusCode != 307 {
+	if resp.StatusCode != 307 || req.s3.IsV4() {
 		return resp
 	}
 
@@ -0,0 +1,84 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package response
+
+import (
+	_fmt "fmt"
+	_http "net/http"
+	_str "strings"
+)
+
+type Error struct {
+	StatusCode uint16
+	Code       string
+	Message    string
+	Resource   string
+	RequestId  string
+	HostId     string
+	Region     string
+}
+
+var (
+	ErrNotFound                = &Error{Code: "NotFound"}
+	ErrNoSuchKey               = &Error{Code: "NoSuchKey"}
+	ErrNoSuchBucket            = &Error{Code: "NoSuchBucket"}
+	ErrNoSuchUpload            = &Error{Code: "NoSuchUpload"}
+	ErrAccessDenied            = &Error{Code: "AccessDenied"}
+	ErrBucketAlreadyExists     = &Error{Code: "BucketAlreadyExists"}
+	ErrBucketAlreadyOwnedByYou = &Error{Code: "BucketAlreadyOwnedByYou"}
+	ErrBucketNotEmpty          = &Error{Code: "BucketNotEmpty"}
+	ErrPreconditionFailed      = &Error{Code: "PreconditionFailed"}
+	ErrNotModified             = &Error{Code: "NotModified"}
+	ErrInvalidRange            = &Error{Code: "InvalidRange"}
+	ErrEntityTooLarge          = &Error{Code: "EntityTooLarge"}
+	ErrSignatureDoesNotMatch   = &Error{Code: "SignatureDoesNotMatch"}
+	ErrSlowDown                = &Error{Code: "SlowDown"}
+)
+
+func (err *Error) Error() string {
+	if err == nil {
+		return ""
+	}
+
+	if err.Message == "" {
+		return _fmt.Sprintf("錯誤，狀態 %d，%s", err.StatusCode, err.Code)
+	}
+
+	return _fmt.Sprintf("錯誤，狀態 %d，%s，Message：%s", err.StatusCode, err.Code, err.Message)
+}
+func (err *Error) Is(target error) bool {
+	if err == nil {
+		return false
+	}
+
+	switch t, ok := target.(*Error); {
+	case !ok || t == nil || t.Code == "":
+		return false
+	case t.Code == ErrNotFound.Code:
+		return err.StatusCode == 404 || err.Code == t.Code
+	case t.Code == ErrPreconditionFailed.Code:
+		return err.StatusCode == 412 || err.Code == t.Code
+	case t.Code == ErrNotModified.Code:
+		return err.StatusCode == 304 || err.Code == t.Code
+	default:
+		return err.Code == t.Code
+	}
+}
+
+func codeOf(status uint16) string {
+	switch status {
+	case 304:
+		return ErrNotModified.Code
+	case 404:
+		return ErrNotFound.Code
+	case 412:
+		return ErrPreconditionFailed.Code
+	default:
+		return _str.Replace(_http.StatusText(int(status)), " ", "", -1)
+	}
+}
@@ -0,0 +1,41 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package response
+
+import (
+	_err "errors"
+	_fmt "fmt"
+	_testing "testing"
+)
+
+func TestErrorIs(t *_testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"相同代碼", &Error{StatusCode: 404, Code: "NoSuchKey"}, ErrNoSuchKey, true},
+		{"不同代碼", &Error{StatusCode: 404, Code: "NoSuchKey"}, ErrNoSuchBucket, false},
+		{"404 皆為 NotFound", &Error{StatusCode: 404, Code: "NoSuchBucket"}, ErrNotFound, true},
+		{"HEAD 的 404", &Error{StatusCode: 404}, ErrNotFound, true},
+		{"412 皆為 PreconditionFailed", &Error{StatusCode: 412}, ErrPreconditionFailed, true},
+		{"304 皆為 NotModified", &Error{StatusCode: 304}, ErrNotModified, true},
+		{"403 不是 NotFound", &Error{StatusCode: 403, Code: "AccessDenied"}, ErrNotFound, false},
+		{"包裝後的錯誤", _fmt.Errorf("取得檔案失敗，Message：%w", &Error{StatusCode: 403, Code: "AccessDenied"}), ErrAccessDenied, true},
+		{"空的代碼", &Error{StatusCode: 500, Code: "InternalError"}, &Error{}, false},
+		{"非 Error 型別", &Error{StatusCode: 500, Code: "InternalError"}, _err.New("InternalError"), false},
+		{"nil", (*Error)(nil), ErrNotFound, false},
+	}
+
+	for _, test := range tests {
+		if got := _err.Is(test.err, test.target); got != test.want {
+			t.Errorf("%s：預期 %t，實際 %t", test.name, test.want, got)
+		}
+	}
+}
@@ -8,8 +8,10 @@
 package response
 
 import (
+	_xml "encoding/xml"
 	_err "errors"
 	_fmt "fmt"
+	_io "io"
 	_str "strings"
 )
 
@@ -17,6 +19,7 @@ type Response struct {
 	StatusCode uint16
 	BodyBytes  []byte
 	BodyString string
+	Body       _io.ReadCloser
 	Error      error
 	Headers    map[string]string
 }
@@ -40,6 +43,7 @@ func (resp *Response) Reset() *Response {
 	resp.StatusCode = uint16(0)
 	resp.BodyBytes = []byte{}
 	resp.BodyString = ""
+	resp.Body = nil
 	resp.Error = nil
 	resp.Headers = map[string]string{}
 	return resp
@@ -65,10 +69,50 @@ func (resp *Response) IsSuccess(statuss ...[]uint16) error {
 		}
 	}
 
+	if err := resp.S3Error(); err != nil {
+		return err
+	}
+
 	tmps := []string{}
 	for _, s := range status {
 		tmps = append(tmps, _fmt.Sprintf("%d", s))
 	}
 
-	return _err.New(_fmt.Sprintf("錯誤，狀態非 %s", _str.Join(tmps, "、")))
+	return &Error{StatusCode: resp.StatusCode, Code: codeOf(resp.StatusCode), Message: _fmt.Sprintf("狀態非 %s", _str.Join(tmps, "、")), RequestId: resp.Headers["X-Amz-Request-Id"], HostId: resp.Headers["X-Amz-Id-2"]}
+}
+func (resp *Response) S3Error() *Error {
+	if resp == nil || resp.Error != nil || resp.StatusCode < 300 {
+		return nil
+	}
+
+	var result *struct {
+		Code      string `xml:"Code"`
+		Message   string `xml:"Message"`
+		Resource  string `xml:"Resource"`
+		RequestId string `xml:"RequestId"`
+		HostId    string `xml:"HostId"`
+		Region    string `xml:"Region"`
+	} = nil
+
+	err := &Error{StatusCode: resp.StatusCode, Code: codeOf(resp.StatusCode), RequestId: resp.Headers["X-Amz-Request-Id"], HostId: resp.Headers["X-Amz-Id-2"], Region: resp.Headers["X-Amz-Bucket-Region"]}
+
+	if e := _xml.Unmarshal(resp.BodyBytes, &result); e != nil || result == nil || result.Code == "" {
+		return err
+	}
+
+	err.Code = result.Code
+	err.Message = result.Message
+	err.Resource = result.Resource
+
+	if result.RequestId != "" {
+		err.RequestId = result.RequestId
+	}
+	if result.HostId != "" {
+		err.HostId = result.HostId
+	}
+	if result.Region != "" {
+		err.Region = result.Region
+	}
+
+	return err
 }
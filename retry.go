@@ -0,0 +1,51 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package s3
+
+import (
+	_rand "math/rand"
+	_sync "sync"
+	_time "time"
+)
+
+type Retry struct {
+	Attempts uint
+	Base     _time.Duration
+	Max      _time.Duration
+}
+
+var (
+	_random = _rand.New(_rand.NewSource(_time.Now().UnixNano()))
+	_lock   = &_sync.Mutex{}
+)
+
+func (retry Retry) AttemptNum() uint {
+	if retry.Attempts == 0 {
+		return 1
+	}
+	return retry.Attempts
+}
+func (retry Retry) Delay(attempt uint) _time.Duration {
+	if retry.Base <= 0 {
+		return 0
+	}
+
+	delay := retry.Base
+	for i := uint(1); i < attempt && delay < _time.Hour && (retry.Max <= 0 || delay < retry.Max); i++ {
+		delay *= 2
+	}
+
+	if retry.Max > 0 && delay > retry.Max {
+		delay = retry.Max
+	}
+
+	_lock.Lock()
+	defer _lock.Unlock()
+
+	return _time.Duration(_random.Int63n(int64(delay) + 1))
+}
@@ -0,0 +1,36 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package s3
+
+import (
+	_testing "testing"
+	_time "time"
+)
+
+func TestRetryDelay(t *_testing.T) {
+	tests := []struct {
+		name    string
+		retry   Retry
+		attempt uint
+		max     _time.Duration
+	}{
+		{"沒有間隔", Retry{Attempts: 3}, 2, 0},
+		{"第一次", Retry{Base: 100 * _time.Millisecond}, 1, 100 * _time.Millisecond},
+		{"指數退避", Retry{Base: 100 * _time.Millisecond}, 4, 800 * _time.Millisecond},
+		{"上限", Retry{Base: 100 * _time.Millisecond, Max: 250 * _time.Millisecond}, 10, 250 * _time.Millisecond},
+		{"次數過多不溢位", Retry{Base: _time.Second}, 200, 2 * _time.Hour},
+	}
+
+	for _, test := range tests {
+		for i := 0; i < 100; i++ {
+			if delay := test.retry.Delay(test.attempt); delay < 0 || delay > test.max {
+				t.Fatalf("%s：間隔 %s 超出範圍 0 ~ %s", test.name, delay, test.max)
+			}
+		}
+	}
+}
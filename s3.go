@@ -8,28 +8,45 @@
 package s3
 
 import (
+	_context "context"
 	_hmac "crypto/hmac"
 	_md5 "crypto/md5"
 	_sha1 "crypto/sha1"
+	_sha256 "crypto/sha256"
 	_base64 "encoding/base64"
 	_hex "encoding/hex"
 	_xml "encoding/xml"
 	_err "errors"
 	_fmt "fmt"
+	_http "net/http"
+	_url "net/url"
 	_bucket "s3/bucket"
 	_enum "s3/enum"
 	_model "s3/model"
 	_req "s3/request"
+	_str "strings"
+	_sync "sync"
 	_time "time"
 )
 
 type S3 struct {
-	_access string
-	_secret string
+	_access    string
+	_secret    string
+	_v4        bool
+	_region    string
+	_scheme    string
+	_host      string
+	_pathStyle bool
+	_regions   map[string]string
+	_mutex     *_sync.RWMutex
+	_ctx       _context.Context
+	_client    *_http.Client
+	_retry     Retry
 }
 
 var (
 	_instances = map[string]*S3{}
+	_locker    = &_sync.Mutex{}
 	_exts      = map[string]string{".jpg": "image/jpeg", ".gif": "image/gif", ".png": "image/png", ".pdf": "application/pdf", ".gz": "application/x-gzip", ".zip": "application/x-zip", ".swf": "application/x-shockwave-flash", ".tar": "application/x-tar", ".bz": "application/x-bzip", ".bz2": "application/x-bzip2", ".txt": "text/plain", ".html": "text/html", ".htm": "text/html", ".ico": "image/x-icon", ".css": "text/css", ".js": "application/x-javascript", ".xml": "text/xml", ".ogg": "application/ogg", ".wav": "audio/x-wav", ".avi": "video/x-msvideo", ".mpg": "video/mpeg", ".mov": "video/quicktime", ".mp3": "audio/mpeg", ".mpeg": "video/mpeg", ".flv": "video/x-flv", ".php": "application/x-httpd-php", ".bin": "application/macbinary", ".psd": "application/x-photoshop", ".ai": "application/postscript", ".ppt": "application/powerpoint", ".wbxml": "application/wbxml", ".tgz": "application/x-tar", ".jpeg": "image/jpeg", ".jpe": "image/jpeg", ".bmp": "image/bmp", ".shtml": "text/html", ".text": "text/plain", ".doc": "application/msword", ".docx": "application/vnd.openxmlformats-officedocument.wordprocessingml.document", ".xlsx": "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", ".word": "application/msword", ".json": "application/json", ".svg": "image/svg+xml", ".mp2": "audio/mpeg", ".exe": "application/octet-stream", ".tif": "image/tiff", ".tiff": "image/tiff", ".asc": "text/plain", ".xsl": "text/xml", ".hqx": "application/mac-binhex40", ".cpt": "application/mac-compactpro", ".csv": "text/x-comma-separated-values", ".dms": "application/octet-stream", ".lha": "application/octet-stream", ".lzh": "application/octet-stream", ".class": "application/octet-stream", ".so": "application/octet-stream", ".sea": "application/octet-stream", ".dll": "application/octet-stream", ".oda": "application/oda", ".eps": "application/postscript", ".ps": "application/postscript", ".smi": "application/smil", ".smil": "application/smil", ".mif": "application/vnd.mif", ".xls": "application/excel", ".wmlc": "application/wmlc", ".dcr": "application/x-director", ".dir": "application/x-director", ".dxr": "application/x-director", ".dvi": "application/x-dvi", ".gtar": "application/x-gtar", ".php4": "application/x-httpd-php", ".php3": "application/x-httpd-php", ".phtml": "application/x-httpd-php", ".phps": "application/x-httpd-php-source", ".sit": "application/x-stuffit", ".xhtml": "application/xhtml+xml", ".xht": "application/xhtml+xml", ".mid": "audio/midi", ".midi": "audio/midi", ".mpga": "audio/mpeg", ".aif": "audio/x-aiff", ".aiff": "audio/x-aiff", ".aifc": "audio/x-aiff", ".ram": "audio/x-pn-realaudio", ".rm": "audio/x-pn-realaudio", ".rpm": "audio/x-pn-realaudio-plugin", ".ra": "audio/x-realaudio", ".rv": "video/vnd.rn-realvideo", ".log": "text/plain", ".rtx": "text/richtext", ".rtf": "text/rtf", ".mpe": "video/mpeg", ".qt": "video/quicktime", ".movie": "video/x-sgi-movie", ".xl": "application/excel", ".eml": "message/rfc822"}
 )
 
@@ -38,36 +55,249 @@ func hashHmacSha1(password string, salt string) []byte {
 	h.Write([]byte(password))
 	return h.Sum(nil)
 }
+func hashHmacSha256(str string, key []byte) []byte {
+	h := _hmac.New(_sha256.New, key)
+	h.Write([]byte(str))
+	return h.Sum(nil)
+}
 
 func Instance(access string, secret string) *S3 {
 	key := _fmt.Sprintf("%s_%s", access, secret)
 	hash := _md5.Sum([]byte(key))
 	key = _hex.EncodeToString(hash[:])
 
+	_locker.Lock()
+	defer _locker.Unlock()
+
 	if instance, ok := _instances[key]; ok {
 		return instance
 	}
 
-	_instances[key] = (&S3{_access: access, _secret: secret})
+	_instances[key] = (&S3{_access: access, _secret: secret, _v4: false, _region: _enum.LOC_NANO.Region(), _regions: map[string]string{}, _mutex: &_sync.RWMutex{}, _ctx: _context.Background(), _retry: Retry{Attempts: 3, Base: 100 * _time.Millisecond, Max: 5 * _time.Second}})
 
 	return _instances[key]
 }
 
 func (s3 *S3) GetS3Interface() {}
+func (s3 *S3) clone() *S3 {
+	instance := *s3
+	return &instance
+}
+func (s3 *S3) WithContext(ctx _context.Context) *S3 {
+	if s3 == nil || ctx == nil {
+		return s3
+	}
+	instance := s3.clone()
+	instance._ctx = ctx
+	return instance
+}
+func (s3 *S3) Context() _context.Context {
+	if s3 == nil || s3._ctx == nil {
+		return _context.Background()
+	}
+	return s3._ctx
+}
+func (s3 *S3) UseV4(useV4 bool) *S3 {
+	if s3 == nil {
+		return s3
+	}
+	instance := s3.clone()
+	instance._v4 = useV4
+	return instance
+}
+func (s3 *S3) Region(loc _enum.Loc) *S3 {
+	if s3 == nil {
+		return s3
+	}
+	instance := s3.clone()
+	instance._region = loc.Region()
+	return instance
+}
+func (s3 *S3) Endpoint(endpoint string, regions ...string) *S3 {
+	if s3 == nil {
+		return s3
+	}
+
+	instance := s3.clone()
+	instance._regions, instance._mutex = map[string]string{}, &_sync.RWMutex{}
+
+	if endpoint == "" {
+		instance._scheme, instance._host = "", ""
+		return instance
+	}
+
+	if !_str.Contains(endpoint, "://") {
+		endpoint = _fmt.Sprintf("https://%s", endpoint)
+	}
+
+	if url, err := _url.Parse(endpoint); err == nil && url.Host != "" {
+		instance._scheme, instance._host = url.Scheme, url.Host
+	}
+
+	if len(regions) > 0 && regions[0] != "" {
+		instance._region = regions[0]
+	}
+
+	return instance
+}
+func (s3 *S3) PathStyle(pathStyle bool) *S3 {
+	if s3 == nil {
+		return s3
+	}
+	instance := s3.clone()
+	instance._pathStyle = pathStyle
+	return instance
+}
+func (s3 *S3) Client(client *_http.Client) *S3 {
+	if s3 == nil {
+		return s3
+	}
+	instance := s3.clone()
+	instance._client = client
+	return instance
+}
+func (s3 *S3) Transport(transport _http.RoundTripper) *S3 {
+	if s3 == nil {
+		return s3
+	}
+
+	client := &_http.Client{}
+	if s3._client != nil {
+		*client = *s3._client
+	}
+	client.Transport = transport
+
+	instance := s3.clone()
+	instance._client = client
+	return instance
+}
+func (s3 *S3) RetryPolicy(retry Retry) *S3 {
+	if s3 == nil {
+		return s3
+	}
+	instance := s3.clone()
+	instance._retry = retry
+	return instance
+}
+func (s3 *S3) IsV4() bool {
+	return s3 != nil && s3._v4
+}
+func (s3 *S3) RegionStr() string {
+	if s3 == nil || s3._region == "" {
+		return _enum.LOC_NANO.Region()
+	}
+	return s3._region
+}
+func (s3 *S3) BucketRegionStr(bucket string) string {
+	if s3 == nil || s3._mutex == nil {
+		return s3.RegionStr()
+	}
+
+	s3._mutex.RLock()
+	defer s3._mutex.RUnlock()
+
+	if region, ok := s3._regions[_str.ToLower(bucket)]; ok {
+		return region
+	}
+	return s3.RegionStr()
+}
+func (s3 *S3) HasBucketRegion(bucket string) bool {
+	if s3 == nil || s3._mutex == nil {
+		return false
+	}
+
+	s3._mutex.RLock()
+	defer s3._mutex.RUnlock()
+
+	_, ok := s3._regions[_str.ToLower(bucket)]
+	return ok
+}
+func (s3 *S3) SetBucketRegion(bucket string, region string) {
+	if s3 == nil || s3._mutex == nil || bucket == "" {
+		return
+	}
+
+	s3._mutex.Lock()
+	defer s3._mutex.Unlock()
+
+	if region == "" {
+		delete(s3._regions, _str.ToLower(bucket))
+	} else {
+		s3._regions[_str.ToLower(bucket)] = region
+	}
+}
+func (s3 *S3) HttpClient() *_http.Client {
+	if s3 == nil {
+		return nil
+	}
+	return s3._client
+}
+func (s3 *S3) RetryAttempts() uint {
+	if s3 == nil {
+		return 1
+	}
+	return s3._retry.AttemptNum()
+}
+func (s3 *S3) RetryDelay(attempt uint) _time.Duration {
+	if s3 == nil {
+		return 0
+	}
+	return s3._retry.Delay(attempt)
+}
+func (s3 *S3) SchemeStr() string {
+	if s3 == nil {
+		return ""
+	}
+	return s3._scheme
+}
+func (s3 *S3) HostStr() string {
+	if s3 == nil {
+		return ""
+	}
+	return s3._host
+}
+func (s3 *S3) IsPathStyle() bool {
+	return s3 != nil && s3._pathStyle
+}
+func (s3 *S3) AccessKey() string {
+	if s3 == nil {
+		return ""
+	}
+	return s3._access
+}
 
 func (s3 *S3) Signature(str string) string {
 	if s3 == nil {
 		return ""
 	}
 
-	return _fmt.Sprintf("AWS %s:%s", s3._access, _base64.StdEncoding.EncodeToString(hashHmacSha1(str, s3._secret)))
+	return _fmt.Sprintf("AWS %s:%s", s3._access, s3.SignatureV2(str))
+}
+func (s3 *S3) SignatureV2(str string) string {
+	if s3 == nil {
+		return ""
+	}
+
+	return _base64.StdEncoding.EncodeToString(hashHmacSha1(str, s3._secret))
+}
+func (s3 *S3) SignatureV4(date string, region string, service string, str string) string {
+	if s3 == nil {
+		return ""
+	}
+
+	key := hashHmacSha256(date, []byte("AWS4"+s3._secret))
+	key = hashHmacSha256(region, key)
+	key = hashHmacSha256(service, key)
+	key = hashHmacSha256("aws4_request", key)
+
+	return _hex.EncodeToString(hashHmacSha256(str, key))
 }
 func (s3 *S3) Test() bool {
-	response := _req.New(s3).Method(_enum.METHOD_GET).UseSSL(true).Response()
+	response := _req.New(s3).Context(s3.Context()).Method(_enum.METHOD_GET).UseSSL(true).Response()
 	return response.Error == nil && response.StatusCode == 200
 }
 func (s3 *S3) Info() (*_model.BucketInfo, error) {
-	response := _req.New(s3).Method(_enum.METHOD_GET).UseSSL(true).Response()
+	response := _req.New(s3).Context(s3.Context()).Method(_enum.METHOD_GET).UseSSL(true).Response()
 
 	if err := response.IsSuccess(); err != nil {
 		return nil, err
@@ -113,7 +343,7 @@ func (s3 *S3) Buckets() ([]string, error) {
 
 	info, err := s3.Info()
 	if err != nil {
-		return buckets, _err.New(_fmt.Sprintf("取得 Info 資訊失敗，Message：%s", err))
+		return buckets, _fmt.Errorf("取得 Info 資訊失敗，Message：%w", err)
 	}
 
 	if info == nil {
@@ -130,3 +360,9 @@ func (s3 *S3) Bucket(name string) *_bucket.Bucket {
 	bucket, _ := _bucket.New(name, s3)
 	return bucket
 }
+func (s3 *S3) Checkpoints(dir string) ([]*_model.Checkpoint, error) {
+	return _bucket.Checkpoints(dir)
+}
+func (s3 *S3) CleanCheckpoints(dir string, expire _time.Duration) ([]*_model.Checkpoint, error) {
+	return _bucket.CleanCheckpoints(s3, dir, expire)
+}
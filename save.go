@@ -0,0 +1,32 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package s3
+
+import (
+	_os "os"
+)
+
+type Save struct {
+	Mode     _os.FileMode
+	KeepTime bool
+	Resume   bool
+}
+
+func (save Save) GetSaveInterface() {}
+func (save Save) ModeVal() *_os.FileMode {
+	if save.Mode == 0 {
+		return nil
+	}
+	return &save.Mode
+}
+func (save Save) IsKeepTime() bool {
+	return save.KeepTime
+}
+func (save Save) IsResume() bool {
+	return save.Resume
+}
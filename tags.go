@@ -0,0 +1,15 @@
+/**
+ * @author      OA Wu <oawu.tw@gmail.com>
+ * @copyright   Copyright (c) 2015 - 2022
+ * @license     http://opensource.org/licenses/MIT  MIT License
+ * @link        https://www.ioa.tw/
+ */
+
+package s3
+
+type Tags map[string]string
+
+func (tags Tags) GetTagsInterface() {}
+func (tags Tags) TagsMap() map[string]string {
+	return tags
+}
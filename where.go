@@ -8,10 +8,13 @@
 package s3
 
 type Where struct {
-	Prefix  string
-	NextKey string
-	Exclude string
-	Limit   uint64
+	Prefix     string
+	NextKey    string
+	Exclude    string
+	Limit      uint64
+	Token      string
+	StartAfter string
+	FetchOwner bool
 }
 
 func (where Where) GetWhereInterface() {}
@@ -39,3 +42,18 @@ func (where Where) LimitNum() *uint64 {
 	}
 	return &where.Limit
 }
+func (where Where) TokenStr() *string {
+	if where.Token == "" {
+		return nil
+	}
+	return &where.Token
+}
+func (where Where) StartAfterStr() *string {
+	if where.StartAfter == "" {
+		return nil
+	}
+	return &where.StartAfter
+}
+func (where Where) IsFetchOwner() bool {
+	return where.FetchOwner
+}